@@ -38,6 +38,28 @@ type Config struct {
 	// avoid repeatedly re-downloading over the Internet.
 	PluginCacheDir string `hcl:"plugin_cache_dir"`
 
+	// DefaultProviderRegistryHost overrides the hostname that unqualified
+	// provider addresses and legacy provider names resolve to. Leave unset
+	// to use the standard public registry.
+	DefaultProviderRegistryHost string `hcl:"default_provider_registry_host"`
+
+	// ProviderTrustedProxyCACertPEM, if set, is an additional PEM-encoded CA
+	// certificate that Terraform will trust, alongside the normal system
+	// trust store, when making provider registry and download requests.
+	// This is for operators behind a TLS-intercepting proxy who want to
+	// trust that proxy's CA for provider traffic specifically, rather than
+	// installing it into the whole system's trust store.
+	ProviderTrustedProxyCACertPEM string `hcl:"provider_trusted_proxy_ca_cert_pem"`
+
+	// ProviderProxyURL, if set, routes all provider registry and download
+	// traffic through the given proxy instead of relying on the usual
+	// HTTP_PROXY/HTTPS_PROXY/ALL_PROXY environment variables. It accepts
+	// "http", "https", or "socks5" schemes, and may include a userinfo
+	// component to authenticate with the proxy. This is for CI systems that
+	// can configure Terraform directly but cannot inject environment
+	// variables into the process that runs it.
+	ProviderProxyURL string `hcl:"provider_proxy_url"`
+
 	Hosts map[string]*ConfigHost `hcl:"host"`
 
 	Credentials        map[string]map[string]interface{}   `hcl:"credentials"`
@@ -277,6 +299,21 @@ func (c1 *Config) Merge(c2 *Config) *Config {
 		result.PluginCacheDir = c2.PluginCacheDir
 	}
 
+	result.DefaultProviderRegistryHost = c1.DefaultProviderRegistryHost
+	if result.DefaultProviderRegistryHost == "" {
+		result.DefaultProviderRegistryHost = c2.DefaultProviderRegistryHost
+	}
+
+	result.ProviderTrustedProxyCACertPEM = c1.ProviderTrustedProxyCACertPEM
+	if result.ProviderTrustedProxyCACertPEM == "" {
+		result.ProviderTrustedProxyCACertPEM = c2.ProviderTrustedProxyCACertPEM
+	}
+
+	result.ProviderProxyURL = c1.ProviderProxyURL
+	if result.ProviderProxyURL == "" {
+		result.ProviderProxyURL = c2.ProviderProxyURL
+	}
+
 	if (len(c1.Hosts) + len(c2.Hosts)) > 0 {
 		result.Hosts = make(map[string]*ConfigHost)
 		for name, host := range c1.Hosts {
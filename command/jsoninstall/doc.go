@@ -0,0 +1,19 @@
+// Package jsoninstall defines the schema of the machine-readable event
+// stream that provider installation can optionally emit, one JSON object
+// per line, when a command such as "terraform init" is run with the
+// -json flag.
+//
+// The types in this package, and the value of FormatVersion, are part of
+// Terraform's public machine-readable output contract: a parser written
+// against a given FormatVersion should continue to work against any later
+// Terraform release with the same major FormatVersion number. Consumers
+// should ignore any JSON object properties they don't recognize, to remain
+// compatible with minor version increments that add optional detail.
+package jsoninstall
+
+// FormatVersion represents the version of this JSON event schema, using
+// semantic versioning. It is incremented for any change to the schema; a
+// change to the major component indicates that existing parsers may need
+// to be updated, while a change to the minor component only adds
+// information that existing parsers can safely ignore.
+const FormatVersion = "1.0"
@@ -0,0 +1,53 @@
+package jsoninstall
+
+// Event is implemented by every event type that can appear in the provider
+// installer's -json event stream. Each line of the stream is the JSON
+// encoding of exactly one Event, and its "type" property identifies which
+// concrete event it is.
+type Event interface {
+	eventType() string
+}
+
+// eventEnvelope is the shape that every event line has in common. Decoders
+// can unmarshal into this first to discover the Type and then unmarshal
+// again into the concrete event type it identifies.
+type eventEnvelope struct {
+	Type string `json:"type"`
+}
+
+// QueryingEvent is emitted when the installer begins querying a source for
+// the versions available for a provider.
+type QueryingEvent struct {
+	Provider string `json:"provider"`
+}
+
+func (QueryingEvent) eventType() string { return "querying" }
+
+// FetchingEvent is emitted when the installer begins downloading the
+// distribution package for a specific provider version.
+type FetchingEvent struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version"`
+}
+
+func (FetchingEvent) eventType() string { return "fetching" }
+
+// InstalledEvent is emitted when a provider package has been successfully
+// verified and installed into the working directory's provider cache.
+type InstalledEvent struct {
+	Provider string   `json:"provider"`
+	Version  string   `json:"version"`
+	Hashes   []string `json:"hashes,omitempty"`
+}
+
+func (InstalledEvent) eventType() string { return "installed" }
+
+// ErrorEvent is emitted when installation of a provider fails. It does not
+// necessarily end the event stream, because the installer may continue
+// attempting to install other providers before giving up.
+type ErrorEvent struct {
+	Provider string `json:"provider,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (ErrorEvent) eventType() string { return "error" }
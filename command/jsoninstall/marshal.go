@@ -0,0 +1,25 @@
+package jsoninstall
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Marshal encodes a single Event as one line of the -json event stream,
+// including the "type" and "@format_version" properties that identify how
+// to interpret it.
+func Marshal(event Event) ([]byte, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %s", event.eventType(), err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	fields["type"] = event.eventType()
+	fields["@format_version"] = FormatVersion
+
+	return json.Marshal(fields)
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/internal/getproviders"
+)
+
+// ProvidersPublishCommand is a Command implementation that packages
+// already-built provider binaries into the artifact layout expected by an
+// internal provider registry or mirror: per-platform zip files, a
+// SHA256SUMS file, and (if a signing key is given) a detached signature.
+//
+// This is a plumbing command intended for use by internal provider
+// publishing pipelines, not by end users managing Terraform configurations.
+type ProvidersPublishCommand struct {
+	Meta
+}
+
+func (c *ProvidersPublishCommand) Help() string {
+	return providersPublishCommandHelp
+}
+
+func (c *ProvidersPublishCommand) Synopsis() string {
+	return "Package built provider binaries for an internal registry"
+}
+
+func (c *ProvidersPublishCommand) Run(args []string) int {
+	var providerAddr, versionStr, outDir, signingKeyFile string
+
+	cmdFlags := c.Meta.defaultFlagSet("providers publish")
+	cmdFlags.StringVar(&providerAddr, "provider", "", "fully-qualified provider address")
+	cmdFlags.StringVar(&versionStr, "version", "", "provider version")
+	cmdFlags.StringVar(&outDir, "out", ".", "directory to write packaged artifacts to")
+	cmdFlags.StringVar(&signingKeyFile, "signing-key", "", "path to an ASCII-armored private key to sign the SHA256SUMS file with")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err))
+		return 1
+	}
+
+	binaryPaths := cmdFlags.Args()
+	if providerAddr == "" || versionStr == "" || len(binaryPaths) == 0 {
+		c.Ui.Error("The providers publish command requires -provider, -version, and at least one <platform>=<path> binary argument.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	provider, diags := addrs.ParseProviderSourceString(providerAddr)
+	if diags.HasErrors() {
+		c.Ui.Error(fmt.Sprintf("Invalid provider address %q: %s\n", providerAddr, diags.Err()))
+		return 1
+	}
+
+	version, err := getproviders.ParseVersion(versionStr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid version %q: %s\n", versionStr, err))
+		return 1
+	}
+
+	artifacts := make([]getproviders.PublishArtifact, 0, len(binaryPaths))
+	for _, arg := range binaryPaths {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			c.Ui.Error(fmt.Sprintf("Invalid binary argument %q: must be <os>_<arch>=<path>.\n", arg))
+			return 1
+		}
+		platform, err := getproviders.ParsePlatform(kv[0])
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid platform %q: %s\n", kv[0], err))
+			return 1
+		}
+		artifacts = append(artifacts, getproviders.PublishArtifact{
+			Platform:   platform,
+			BinaryPath: kv[1],
+		})
+	}
+
+	var armoredSigningKey string
+	if signingKeyFile != "" {
+		key, err := ioutil.ReadFile(signingKeyFile)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read signing key: %s\n", err))
+			return 1
+		}
+		armoredSigningKey = string(key)
+	}
+
+	packages, err := getproviders.PublishRelease(provider, version, artifacts, outDir, armoredSigningKey)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to publish release: %s\n", err))
+		return 1
+	}
+
+	for _, p := range packages {
+		c.Ui.Output(fmt.Sprintf("Packaged %s (%x)", p.Filename, p.SHA256Sum))
+	}
+
+	return 0
+}
+
+const providersPublishCommandHelp = `
+Usage: terraform providers publish -provider=ADDR -version=VERSION [options] <platform>=<path>...
+
+  Packages one or more already-built provider binaries into the zip and
+  checksum artifacts an internal registry or filesystem/network mirror
+  expects, optionally signing the checksum file.
+
+  This is a plumbing command intended for internal provider publishing
+  pipelines and is not needed for normal use of Terraform.
+
+Options:
+
+  -provider=ADDR       Fully-qualified provider address (hostname/namespace/type).
+  -version=VERSION      Provider version being published.
+  -out=DIR              Directory to write the packaged artifacts to. Defaults to the current directory.
+  -signing-key=PATH      Path to an ASCII-armored private key to sign the SHA256SUMS file with.
+`
@@ -86,11 +86,12 @@ func initCommands(config *cliconfig.Config, services *disco.Disco, providerSrc g
 	// that to match.
 
 	PlumbingCommands = map[string]struct{}{
-		"state":        struct{}{}, // includes all subcommands
-		"debug":        struct{}{}, // includes all subcommands
-		"force-unlock": struct{}{},
-		"push":         struct{}{},
-		"0.12upgrade":  struct{}{},
+		"state":             struct{}{}, // includes all subcommands
+		"debug":             struct{}{}, // includes all subcommands
+		"force-unlock":      struct{}{},
+		"push":              struct{}{},
+		"0.12upgrade":       struct{}{},
+		"providers publish": struct{}{},
 	}
 
 	Commands = map[string]cli.CommandFactory{
@@ -214,6 +215,12 @@ func initCommands(config *cliconfig.Config, services *disco.Disco, providerSrc g
 			}, nil
 		},
 
+		"providers publish": func() (cli.Command, error) {
+			return &command.ProvidersPublishCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"providers schema": func() (cli.Command, error) {
 			return &command.ProvidersSchemaCommand{
 				Meta: meta,
@@ -9,10 +9,18 @@ import (
 // New returns the DefaultPooledClient from the cleanhttp
 // package that will also send a Terraform User-Agent string.
 func New() *http.Client {
-	cli := cleanhttp.DefaultPooledClient()
-	cli.Transport = &userAgentRoundTripper{
-		userAgent: UserAgentString(),
-		inner:     cli.Transport,
+	return NewWithTransport(cleanhttp.DefaultPooledTransport())
+}
+
+// NewWithTransport is like New but sends requests through the given
+// transport instead of cleanhttp's default, for callers that need to
+// customize transport-level behavior (such as which TLS root certificates
+// are trusted) while still getting Terraform's usual User-Agent handling.
+func NewWithTransport(transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport: &userAgentRoundTripper{
+			userAgent: UserAgentString(),
+			inner:     transport,
+		},
 	}
-	return cli
 }
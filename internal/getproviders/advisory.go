@@ -0,0 +1,179 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// Severity is the severity level of a published security advisory against
+// a provider version, ordered from weakest to strongest so that a
+// threshold can be expressed with Go's usual relational operators.
+type Severity int
+
+const (
+	SeverityLow Severity = iota
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// ParseSeverity parses the case-insensitive severity names used in an
+// advisory feed ("low", "medium", "high", "critical").
+func ParseSeverity(str string) (Severity, error) {
+	switch strings.ToLower(str) {
+	case "low":
+		return SeverityLow, nil
+	case "medium":
+		return SeverityMedium, nil
+	case "high":
+		return SeverityHigh, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return 0, fmt.Errorf("unrecognized severity %q", str)
+	}
+}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityHigh:
+		return "high"
+	case SeverityMedium:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// Advisory is a single published security advisory against a specific
+// provider version.
+type Advisory struct {
+	Provider addrs.Provider
+	Version  Version
+	ID       string
+	Severity Severity
+	Summary  string
+}
+
+// AdvisoryList is a set of published security advisories against provider
+// versions, as might be served by a mirror or a corporate security team's
+// own feed alongside the usual index.
+//
+// Unlike RevocationList, appearing in an AdvisoryList does not by itself
+// mean a version must not be installed; CheckAdvisories applies a
+// configurable AdvisoryPolicy to decide whether each advisory found should
+// block installation, only warn about it, or be ignored outright.
+type AdvisoryList struct {
+	byProviderVersion map[addrs.Provider]map[string][]Advisory
+}
+
+type advisoryListJSON struct {
+	Advisories []struct {
+		Provider string `json:"provider"`
+		Version  string `json:"version"`
+		ID       string `json:"id"`
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+	} `json:"advisories"`
+}
+
+// ParseAdvisoryList reads an advisory feed document.
+func ParseAdvisoryList(r io.Reader) (*AdvisoryList, error) {
+	var raw advisoryListJSON
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid advisory feed: %s", err)
+	}
+
+	list := &AdvisoryList{
+		byProviderVersion: make(map[addrs.Provider]map[string][]Advisory),
+	}
+	for _, item := range raw.Advisories {
+		provider, diags := addrs.ParseProviderSourceString(item.Provider)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("invalid provider address %q in advisory feed: %s", item.Provider, diags.Err())
+		}
+		version, err := ParseVersion(item.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q for %s in advisory feed: %s", item.Version, item.Provider, err)
+		}
+		severity, err := ParseSeverity(item.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid severity for advisory %s against %s: %s", item.ID, item.Provider, err)
+		}
+
+		advisory := Advisory{
+			Provider: provider,
+			Version:  version,
+			ID:       item.ID,
+			Severity: severity,
+			Summary:  item.Summary,
+		}
+
+		versions, ok := list.byProviderVersion[provider]
+		if !ok {
+			versions = make(map[string][]Advisory)
+			list.byProviderVersion[provider] = versions
+		}
+		versions[version.String()] = append(versions[version.String()], advisory)
+	}
+	return list, nil
+}
+
+// For returns the advisories recorded against provider at version.
+func (l *AdvisoryList) For(provider addrs.Provider, version Version) []Advisory {
+	if l == nil {
+		return nil
+	}
+	return l.byProviderVersion[provider][version.String()]
+}
+
+// AdvisoryPolicy configures how CheckAdvisories enforces an AdvisoryList.
+type AdvisoryPolicy struct {
+	// BlockAtOrAbove is the severity level, inclusive, at which
+	// CheckAdvisories refuses installation. The zero value, SeverityLow,
+	// blocks on every advisory; set it above SeverityCritical to disable
+	// blocking entirely and only ever warn.
+	BlockAtOrAbove Severity
+
+	// WarnAtOrAbove is the severity level, inclusive, at which
+	// CheckAdvisories reports a warning for an advisory that isn't already
+	// being blocked.
+	WarnAtOrAbove Severity
+
+	// AcceptedAdvisoryIDs lists advisory IDs a security team has reviewed
+	// and decided to accept the risk of, exempting them from both blocking
+	// and warning regardless of severity.
+	AcceptedAdvisoryIDs map[string]bool
+}
+
+// CheckAdvisories applies policy to every advisory list has recorded
+// against provider at version, returning a non-nil error for the first
+// advisory that meets the blocking threshold and isn't accepted, and a
+// warning message for each advisory that meets the warning threshold but
+// not the blocking one.
+func CheckAdvisories(list *AdvisoryList, policy AdvisoryPolicy, provider addrs.Provider, version Version) (warnings []string, err error) {
+	for _, advisory := range list.For(provider, version) {
+		if policy.AcceptedAdvisoryIDs[advisory.ID] {
+			continue
+		}
+		switch {
+		case advisory.Severity >= policy.BlockAtOrAbove:
+			return warnings, fmt.Errorf(
+				"provider %s %s has a %s-severity advisory %s: %s",
+				provider, version, advisory.Severity, advisory.ID, advisory.Summary,
+			)
+		case advisory.Severity >= policy.WarnAtOrAbove:
+			warnings = append(warnings, fmt.Sprintf(
+				"provider %s %s has a %s-severity advisory %s: %s",
+				provider, version, advisory.Severity, advisory.ID, advisory.Summary,
+			))
+		}
+	}
+	return warnings, nil
+}
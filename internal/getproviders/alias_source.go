@@ -0,0 +1,54 @@
+package getproviders
+
+import (
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// AliasedSource is a Source that wraps another Source and rewrites the
+// hostname of outgoing requests according to a fixed alias table, so that
+// traffic for a canonical hostname (such as the public registry) can be
+// redirected to an internal mirror without requiring any changes to
+// configuration or the lock file, both of which continue to refer to
+// providers by their canonical addresses.
+type AliasedSource struct {
+	underlying Source
+	aliases    map[svchost.Hostname]svchost.Hostname
+}
+
+var _ Source = (*AliasedSource)(nil)
+
+// NewAliasedSource constructs and returns a new AliasedSource that wraps
+// the given underlying source, redirecting requests for any hostname found
+// as a key in aliases to the corresponding value.
+//
+// Hostnames not present in aliases are passed through to the underlying
+// source unchanged.
+func NewAliasedSource(underlying Source, aliases map[svchost.Hostname]svchost.Hostname) *AliasedSource {
+	return &AliasedSource{
+		underlying: underlying,
+		aliases:    aliases,
+	}
+}
+
+func (s *AliasedSource) resolve(provider addrs.Provider) addrs.Provider {
+	if real, ok := s.aliases[provider.Hostname]; ok {
+		provider.Hostname = real
+	}
+	return provider
+}
+
+// AvailableVersions retrieves the available versions for the given
+// provider from the underlying source, after rewriting its hostname
+// according to the alias table.
+func (s *AliasedSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	return s.underlying.AvailableVersions(s.resolve(provider))
+}
+
+// PackageMeta retrieves package metadata for the given provider from the
+// underlying source, after rewriting its hostname according to the alias
+// table.
+func (s *AliasedSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	return s.underlying.PackageMeta(s.resolve(provider), version, target)
+}
@@ -0,0 +1,243 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// AWSCodeArtifactSource is a Source that reads provider packages published
+// to an AWS CodeArtifact generic-format repository, obtaining its
+// short-lived bearer token by signing a GetAuthorizationToken request with
+// credentials from Session's chain -- the environment, a shared
+// credentials file, an assumed role, or an EC2/ECS instance profile --
+// rather than requiring a long-lived token to be configured by hand.
+//
+// The generated AWS SDK client for CodeArtifact isn't vendored into this
+// module, so this source calls the service's REST API directly, signed
+// with the same aws-sdk-go v4 signer the vendored S3 and DynamoDB clients
+// use internally.
+//
+// A provider's packages are expected to have been published as generic
+// CodeArtifact assets under a package named "terraform-provider-<type>",
+// with each platform's zip archive attached as an asset named the same as
+// packedFilename produces.
+type AWSCodeArtifactSource struct {
+	// Session supplies the credentials used to sign requests.
+	Session *session.Session
+
+	Domain      string
+	DomainOwner string
+	Repository  string
+	Region      string
+
+	// Namespace scopes the CodeArtifact generic package namespace
+	// providers are published under. An empty Namespace matches
+	// CodeArtifact's own convention for a package with no namespace.
+	Namespace string
+
+	// Endpoint and ControlPlaneEndpoint override the repository and
+	// GetAuthorizationToken base URLs respectively, which are otherwise
+	// derived from Domain, DomainOwner, and Region following AWS's own
+	// naming convention. Both exist mainly so tests can point this source
+	// at an httptest server instead of the real AWS endpoints.
+	Endpoint             string
+	ControlPlaneEndpoint string
+
+	// HTTPClient is used to make requests. A nil HTTPClient uses
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+var _ Source = (*AWSCodeArtifactSource)(nil)
+
+type codeArtifactAuthorizationTokenResponse struct {
+	AuthorizationToken string `json:"authorizationToken"`
+	Expiration         int64  `json:"expiration"`
+}
+
+type codeArtifactListVersionsResponse struct {
+	Versions []struct {
+		Version string `json:"version"`
+	} `json:"versions"`
+}
+
+// AvailableVersions lists the versions CodeArtifact has published for
+// provider, by listing the generic package's versions.
+func (s *AWSCodeArtifactSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	endpoint := fmt.Sprintf(
+		"%s/v1/package/generic/%s/%s/versions",
+		s.repositoryEndpoint(), s.Namespace, codeArtifactPackageName(provider),
+	)
+
+	var listing codeArtifactListVersionsResponse
+	if err := s.getJSON(endpoint, &listing); err != nil {
+		return nil, err
+	}
+
+	var result VersionList
+	for _, v := range listing.Versions {
+		version, err := ParseVersion(v.Version)
+		if err != nil {
+			continue
+		}
+		result = append(result, version)
+	}
+	result.Sort()
+	return result, nil
+}
+
+// PackageMeta builds the metadata for a single provider package, pointing
+// at its CodeArtifact generic asset download URL.
+//
+// Downloading that URL requires the same bearer token this source uses to
+// list versions, attached as an "Authorization: Bearer <token>" header;
+// CodeArtifact has no equivalent of a pre-signed, unauthenticated download
+// URL for a generic asset.
+func (s *AWSCodeArtifactSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	filename := packedFilename(provider.Type, version, target)
+	downloadURL := fmt.Sprintf(
+		"%s/v1/package/generic/%s/%s/version/%s/asset?asset=%s",
+		s.repositoryEndpoint(), s.Namespace, codeArtifactPackageName(provider), version.String(), filename,
+	)
+
+	return PackageMeta{
+		TargetPlatform: target,
+		Filename:       filename,
+		Location:       PackageHTTPURL(downloadURL),
+	}, nil
+}
+
+func (s *AWSCodeArtifactSource) repositoryEndpoint() string {
+	if s.Endpoint != "" {
+		return s.Endpoint
+	}
+	return fmt.Sprintf("https://%s-%s.d.codeartifact.%s.amazonaws.com", s.Domain, s.DomainOwner, s.Region)
+}
+
+func (s *AWSCodeArtifactSource) controlPlaneEndpoint() string {
+	if s.ControlPlaneEndpoint != "" {
+		return s.ControlPlaneEndpoint
+	}
+	return fmt.Sprintf("https://codeartifact.%s.amazonaws.com", s.Region)
+}
+
+func (s *AWSCodeArtifactSource) getJSON(endpoint string, out interface{}) error {
+	token, err := s.authorizationToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ErrHostUnreachable{Hostname: s.hostname(), Wrapped: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return ErrUnauthorized{Hostname: s.hostname(), HaveCredentials: s.Session != nil}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("AWS CodeArtifact repository at %s responded with status %s", endpoint, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response from AWS CodeArtifact: %s", err)
+	}
+	return nil
+}
+
+// authorizationToken returns a bearer token for the repository endpoint,
+// requesting and caching a fresh one from the CodeArtifact control plane
+// when none is cached or the cached one is about to expire.
+func (s *AWSCodeArtifactSource) authorizationToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.tokenExpiry.Add(-time.Minute)) {
+		return s.token, nil
+	}
+
+	if s.Session == nil {
+		return "", ErrUnauthorized{Hostname: s.hostname(), HaveCredentials: false}
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/v1/authorization-token?domain=%s&domain-owner=%s",
+		s.controlPlaneEndpoint(), s.Domain, s.DomainOwner,
+	)
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+
+	signer := v4.NewSigner(s.Session.Config.Credentials)
+	if _, err := signer.Sign(req, nil, "codeartifact", s.Region, time.Now()); err != nil {
+		return "", fmt.Errorf("failed to sign AWS CodeArtifact request: %s", err)
+	}
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", ErrHostUnreachable{Hostname: s.hostname(), Wrapped: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrUnauthorized{Hostname: s.hostname(), HaveCredentials: true}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("AWS CodeArtifact GetAuthorizationToken responded with status %s", resp.Status)
+	}
+
+	var authResp codeArtifactAuthorizationTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return "", fmt.Errorf("invalid GetAuthorizationToken response: %s", err)
+	}
+
+	s.token = authResp.AuthorizationToken
+	s.tokenExpiry = time.Unix(authResp.Expiration, 0)
+	return s.token, nil
+}
+
+func (s *AWSCodeArtifactSource) hostname() svchost.Hostname {
+	hostname, err := svchost.ForComparison(strings.TrimPrefix(s.repositoryEndpoint(), "https://"))
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+// codeArtifactPackageName is the conventional CodeArtifact generic package
+// name a provider's packages are expected to be published under.
+func codeArtifactPackageName(provider addrs.Provider) string {
+	return "terraform-provider-" + provider.Type
+}
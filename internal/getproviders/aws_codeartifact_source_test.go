@@ -0,0 +1,77 @@
+package getproviders
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestAWSCodeArtifactSource(t *testing.T) {
+	var gotAuthHeader string
+	repo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthHeader = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"versions": [{"version": "1.0.0"}, {"version": "1.1.0"}]}`)
+	}))
+	defer repo.Close()
+
+	controlPlane := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"authorizationToken": "example-token", "expiration": 9999999999}`)
+	}))
+	defer controlPlane.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("AKIAEXAMPLE", "secret", ""),
+	})
+	if err != nil {
+		t.Fatalf("failed to create session: %s", err)
+	}
+
+	source := &AWSCodeArtifactSource{
+		Session:              sess,
+		Domain:               "example",
+		DomainOwner:          "123456789012",
+		Repository:           "providers",
+		Region:               "us-east-1",
+		Endpoint:             repo.URL,
+		ControlPlaneEndpoint: controlPlane.URL,
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "acme", "null")
+	versions, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(versions) != 2 || versions[0].String() != "1.0.0" || versions[1].String() != "1.1.0" {
+		t.Fatalf("wrong versions: got %v", versions)
+	}
+	if gotAuthHeader != "Bearer example-token" {
+		t.Errorf("wrong Authorization header: got %q", gotAuthHeader)
+	}
+
+	meta, err := source.PackageMeta(provider, versions[1], Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantURL := PackageHTTPURL(repo.URL + "/v1/package/generic//terraform-provider-null/version/1.1.0/asset?asset=terraform-provider-null_1.1.0_linux_amd64.zip")
+	if meta.Location != wantURL {
+		t.Errorf("wrong download URL:\ngot:  %s\nwant: %s", meta.Location, wantURL)
+	}
+}
+
+func TestAWSCodeArtifactSourceNoCredentials(t *testing.T) {
+	source := &AWSCodeArtifactSource{Domain: "example", DomainOwner: "123456789012", Region: "us-east-1"}
+	provider := addrs.NewProvider(DefaultRegistryHost, "acme", "null")
+
+	_, err := source.AvailableVersions(provider)
+	if _, ok := err.(ErrUnauthorized); !ok {
+		t.Fatalf("wrong error type %T: %s", err, err)
+	}
+}
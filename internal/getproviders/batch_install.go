@@ -0,0 +1,239 @@
+package getproviders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// BatchInstallRequest describes the provider requirements of a single root
+// module as part of a call to ResolveBatch.
+type BatchInstallRequest struct {
+	// Dir identifies the root module these requirements belong to. It is
+	// opaque to ResolveBatch and LinkBatchInstalls, which only use it to
+	// report which root modules depend on a given resolution and to build
+	// per-root-module paths; callers typically set it to the root module's
+	// configuration directory.
+	Dir string
+
+	Requirements []ResolutionRequest
+}
+
+// BatchResolution is the outcome of resolving a single provider's combined
+// requirements across every root module that requires it.
+type BatchResolution struct {
+	ResolutionResult
+
+	// Dirs lists every root module that requires this provider, in the
+	// order its requirement was first seen.
+	Dirs []string
+}
+
+// ResolveBatch unions the provider requirements of many root modules and
+// resolves each distinct provider exactly once, rather than running a
+// separate resolution pass per root module the way N independent init runs
+// would.
+//
+// A provider required by more than one root module is resolved against the
+// conjunction of their Acceptable functions, so the single version
+// ResolveBatch selects satisfies every root module that requires it. All
+// requests for a given provider must agree on Target, since a single
+// resolution pass can only select one package per provider; ResolveBatch
+// returns an error immediately if they disagree.
+//
+// Actually downloading the resolved packages into a shared cache remains
+// the caller's responsibility, consistent with the rest of this package
+// dealing only in provider metadata. Once that's done, LinkBatchInstalls
+// can be used to make each root module's own provider directory see the
+// shared cache's packages without duplicating them on disk.
+func ResolveBatch(source Source, reqs []BatchInstallRequest) ([]BatchResolution, error) {
+	type combined struct {
+		req  ResolutionRequest
+		dirs []string
+	}
+
+	var order []addrs.Provider
+	byProvider := make(map[addrs.Provider]*combined)
+
+	for _, dirReq := range reqs {
+		for _, req := range dirReq.Requirements {
+			existing, ok := byProvider[req.Provider]
+			if !ok {
+				order = append(order, req.Provider)
+				reqCopy := req
+				byProvider[req.Provider] = &combined{req: reqCopy, dirs: []string{dirReq.Dir}}
+				continue
+			}
+
+			if existing.req.Target != req.Target {
+				return nil, fmt.Errorf(
+					"conflicting target platforms requested for %s: %s and %s",
+					req.Provider, existing.req.Target, req.Target,
+				)
+			}
+
+			existing.dirs = append(existing.dirs, dirReq.Dir)
+			if req.Acceptable != nil {
+				prevAcceptable := existing.req.Acceptable
+				newAcceptable := req.Acceptable
+				existing.req.Acceptable = func(v Version) bool {
+					return (prevAcceptable == nil || prevAcceptable(v)) && newAcceptable(v)
+				}
+			}
+		}
+	}
+
+	unioned := make([]ResolutionRequest, len(order))
+	for i, provider := range order {
+		unioned[i] = byProvider[provider].req
+	}
+
+	results := ResolvePackages(source, unioned)
+
+	batch := make([]BatchResolution, len(results))
+	for i, result := range results {
+		batch[i] = BatchResolution{
+			ResolutionResult: result,
+			Dirs:             byProvider[order[i]].dirs,
+		}
+	}
+	return batch, nil
+}
+
+// LinkBatchInstalls hard-links each package in installed (which is expected
+// to have been populated from the shared cache directory, e.g. by
+// SearchInstalledProviders)
+// into the provider directory of every root module that requires it
+// according to batches, falling back to copying the package's files if the
+// shared cache and a root module's provider directory turn out to be on
+// different filesystems.
+//
+// rootModuleProviderDir is typically a closure over each root module's
+// .terraform/providers directory, using the same hostname/namespace/type/
+// version/os_arch layout SearchInstalledProviders expects.
+//
+// LinkBatchInstalls runs with no InstallHooks; use
+// LinkBatchInstallsWithHooks directly if an embedder needs to observe or
+// veto individual links.
+func LinkBatchInstalls(installed []Installed, batches []BatchResolution, rootModuleProviderDir func(dir string) string) ([]Installed, error) {
+	return LinkBatchInstallsWithHooks(installed, batches, rootModuleProviderDir, InstallHooks{})
+}
+
+// InstallHooks lets an embedder observe, annotate, or veto each individual
+// package link performed by LinkBatchInstallsWithHooks, the install-side
+// counterpart to SourceHooks for query-side extension.
+//
+// Every field is optional; a nil field means that phase has no hook to
+// run.
+type InstallHooks struct {
+	// BeforeLink is called before a resolved package is linked from the
+	// shared cache into a root module's provider directory. Returning a
+	// non-nil error vetoes that one link: LinkBatchInstallsWithHooks stops
+	// and returns the error immediately, just as it would for a filesystem
+	// failure.
+	BeforeLink func(src Installed, destDir string) error
+
+	// AfterLink is called once a link attempt has completed, either by
+	// succeeding or by failing with a non-nil err, for bookkeeping such as
+	// inventory registration. It cannot itself veto the outcome, since the
+	// link (or its failure) has already happened by the time it runs.
+	AfterLink func(src Installed, destDir string, err error)
+
+	// FileAttributes configures extended attribute or ACL handling for
+	// files that have to be copied rather than hard-linked. It has no
+	// effect on a successful hard link, since that shares the source
+	// file's attributes automatically.
+	FileAttributes FileAttributeSettings
+}
+
+// LinkBatchInstallsWithHooks behaves like LinkBatchInstalls, except that it
+// runs hooks's callbacks around each individual package link, allowing an
+// embedder to observe, annotate, or veto installs without forking this
+// function.
+func LinkBatchInstallsWithHooks(installed []Installed, batches []BatchResolution, rootModuleProviderDir func(dir string) string, hooks InstallHooks) ([]Installed, error) {
+	byProviderVersion := make(map[addrs.Provider]map[string]Installed)
+	for _, inst := range installed {
+		versions, ok := byProviderVersion[inst.Provider]
+		if !ok {
+			versions = make(map[string]Installed)
+			byProviderVersion[inst.Provider] = versions
+		}
+		versions[inst.Version.String()] = inst
+	}
+
+	var linked []Installed
+	for _, batch := range batches {
+		if batch.Err != nil {
+			continue
+		}
+		src, found := byProviderVersion[batch.Provider][batch.Version.String()]
+		if !found {
+			return linked, fmt.Errorf("no installed package found in shared cache for %s %s", batch.Provider, batch.Version)
+		}
+
+		for _, dir := range batch.Dirs {
+			destDir := rootModuleProviderDir(dir)
+
+			if hooks.BeforeLink != nil {
+				if err := hooks.BeforeLink(src, destDir); err != nil {
+					return linked, fmt.Errorf("install of %s into %s vetoed: %s", batch.Provider, dir, err)
+				}
+			}
+
+			err := linkPackageDir(src.PackageDir, destDir, hooks.FileAttributes)
+			if hooks.AfterLink != nil {
+				hooks.AfterLink(src, destDir, err)
+			}
+			if err != nil {
+				return linked, fmt.Errorf("failed to link %s into %s: %s", batch.Provider, dir, err)
+			}
+
+			linked = append(linked, Installed{
+				Provider:   src.Provider,
+				Version:    src.Version,
+				Platform:   src.Platform,
+				PackageDir: destDir,
+			})
+		}
+	}
+
+	return linked, nil
+}
+
+// linkPackageDir makes every file directly inside srcDir appear in destDir,
+// preferring hard links and falling back to copying when the two
+// directories are on different filesystems. Subdirectories of srcDir are
+// skipped, along with everything inside them.
+//
+// A hard link shares the source file's extended attributes and ACLs
+// automatically, so attrs is only applied to files that had to go through
+// the copy fallback.
+func linkPackageDir(srcDir, destDir string, attrs FileAttributeSettings) error {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+
+	entries, err := readDirIfExists(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		srcPath := filepath.Join(srcDir, entry.Name())
+		destPath := filepath.Join(destDir, entry.Name())
+
+		if err := os.Link(srcPath, destPath); err != nil {
+			if err := copyFileContents(srcPath, destPath); err != nil {
+				return err
+			}
+			if err := applyFileAttributes(srcPath, destPath, attrs); err != nil {
+				return fmt.Errorf("failed to apply file attributes to %s: %s", destPath, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// cacheLockFilename is the name of the advisory lock file
+// AcquireCacheLock creates directly inside a shared cache or mirror
+// directory, such as the one several git worktrees of the same repository
+// might point their plugin cache or filesystem mirror at.
+const cacheLockFilename = ".terraform-plugin-cache.lock"
+
+// cacheLockStaleAfter is how long an existing lock file is trusted before
+// it's assumed to have been left behind by a process that crashed or was
+// killed without releasing it, and is safe to steal.
+const cacheLockStaleAfter = 1 * time.Hour
+
+// CacheLock represents a held advisory lock on a shared provider cache or
+// mirror directory, obtained by AcquireCacheLock.
+type CacheLock struct {
+	path string
+}
+
+// AcquireCacheLock obtains an advisory lock on the given cache directory,
+// so that concurrent Terraform processes that happen to share a single
+// provider cache or filesystem mirror -- the common case when several git
+// worktrees of the same repository are initialized at around the same time
+// -- serialize their writes instead of racing to populate the same
+// version directories and corrupting one another's downloads.
+//
+// It retries acquiring the lock until it succeeds or until timeout elapses,
+// at which point it returns an error. A lock file older than
+// cacheLockStaleAfter is treated as abandoned by a process that didn't
+// shut down cleanly and is stolen rather than waited on.
+//
+// The caller must call Release on the returned CacheLock once it's done
+// writing to the directory.
+func AcquireCacheLock(dir string, timeout time.Duration) (*CacheLock, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %s", dir, err)
+	}
+	path := filepath.Join(dir, cacheLockFilename)
+
+	if err := acquireAdvisoryLockFile(path, cacheLockStaleAfter, timeout); err != nil {
+		return nil, err
+	}
+	return &CacheLock{path: path}, nil
+}
+
+// acquireAdvisoryLockFile creates path exclusively as an empty-or-PID
+// marker file, retrying until it succeeds or timeout elapses, and treating
+// an existing lock file older than staleAfter as abandoned and safe to
+// steal rather than wait on. It's the shared mechanics behind both
+// AcquireCacheLock, which locks a whole shared cache directory, and
+// AcquirePackageLock, which locks a single package version's corner of
+// one.
+func acquireAdvisoryLockFile(path string, staleAfter, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return nil
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("failed to create lock file %s: %s", path, err)
+		}
+
+		if stealStaleLockFile(path, staleAfter) {
+			continue // try again immediately, now that the stale lock is gone
+		}
+
+		if time.Now().After(deadline) {
+			if pid := cacheLockHolderPID(path); pid != 0 {
+				return fmt.Errorf("timed out waiting for lock file %s held by process %d", path, pid)
+			}
+			return fmt.Errorf("timed out waiting for lock file %s held by another process", path)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// stealStaleLockFile removes path if it's older than staleAfter, returning
+// whether it did so.
+func stealStaleLockFile(path string, staleAfter time.Duration) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false // can't tell, so leave it for the next poll
+	}
+	if time.Since(info.ModTime()) < staleAfter {
+		return false
+	}
+	return os.Remove(path) == nil
+}
+
+// Release removes the lock file, allowing another process waiting in
+// AcquireCacheLock to proceed.
+func (l *CacheLock) Release() error {
+	return os.Remove(l.path)
+}
+
+// WithCacheLock acquires a lock on dir, as AcquireCacheLock does, runs f,
+// and releases the lock before returning, regardless of whether f
+// succeeded. It's the right shape for the common case of downloading and
+// linking a batch of resolved packages into a shared cache, where the
+// whole sequence -- not just one write -- needs to run without another
+// worktree's init interleaving with it.
+func WithCacheLock(dir string, timeout time.Duration, f func() error) error {
+	lock, err := AcquireCacheLock(dir, timeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	return f()
+}
+
+// cacheLockHolderPID reads the process ID recorded in an existing lock
+// file, for inclusion in diagnostic messages. It returns 0 if the file
+// can't be read or doesn't contain a valid PID.
+func cacheLockHolderPID(path string) int {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(string(trimTrailingNewline(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
+
+func trimTrailingNewline(data []byte) []byte {
+	for len(data) > 0 && (data[len(data)-1] == '\n' || data[len(data)-1] == '\r') {
+		data = data[:len(data)-1]
+	}
+	return data
+}
@@ -0,0 +1,90 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireCacheLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-cache-lock")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	lock, err := AcquireCacheLock(dir, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, cacheLockFilename)); err != nil {
+		t.Fatalf("lock file was not created: %s", err)
+	}
+
+	if _, err := AcquireCacheLock(dir, 200*time.Millisecond); err == nil {
+		t.Fatalf("expected second acquire to time out while first is held")
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("unexpected error releasing lock: %s", err)
+	}
+
+	lock2, err := AcquireCacheLock(dir, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error re-acquiring released lock: %s", err)
+	}
+	lock2.Release()
+}
+
+func TestAcquireCacheLockStale(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-cache-lock")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, cacheLockFilename)
+	if err := ioutil.WriteFile(path, []byte("12345\n"), 0644); err != nil {
+		t.Fatalf("failed to write stale lock file: %s", err)
+	}
+	staleTime := time.Now().Add(-2 * cacheLockStaleAfter)
+	if err := os.Chtimes(path, staleTime, staleTime); err != nil {
+		t.Fatalf("failed to backdate lock file: %s", err)
+	}
+
+	lock, err := AcquireCacheLock(dir, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error stealing stale lock: %s", err)
+	}
+	lock.Release()
+}
+
+func TestWithCacheLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-cache-lock")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var ran bool
+	err = WithCacheLock(dir, time.Second, func() error {
+		ran = true
+		if _, err := os.Stat(filepath.Join(dir, cacheLockFilename)); err != nil {
+			t.Errorf("lock file missing while f is running: %s", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ran {
+		t.Fatalf("f was not called")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, cacheLockFilename)); !os.IsNotExist(err) {
+		t.Fatalf("lock file still present after WithCacheLock returned")
+	}
+}
@@ -0,0 +1,106 @@
+package getproviders
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// ChaosConfig configures the failure modes ChaosSource injects.
+type ChaosConfig struct {
+	// Latency, if non-zero, is added as an artificial delay before every
+	// call completes, to exercise timeout handling.
+	Latency time.Duration
+
+	// ErrorRate is the probability, in the range [0,1], that a call fails
+	// outright with a synthetic error instead of returning Wrapped's real
+	// result.
+	ErrorRate float64
+
+	// CorruptHashRate is the probability, in the range [0,1], that a
+	// successful PackageMeta call has its SHA256Sum corrupted, simulating a
+	// download that doesn't match its advertised hash -- whether from bit
+	// flips in transit, a truncated download, or a compromised mirror --
+	// so that callers can verify their checksum verification actually
+	// rejects the package rather than only being exercised against
+	// well-behaved fixtures.
+	CorruptHashRate float64
+
+	// Rand supplies the randomness used to decide whether to inject a
+	// failure on each call. If nil, NewChaosSource seeds one from the
+	// current time. Tests that need reproducible chaos should supply their
+	// own seeded *rand.Rand here.
+	Rand *rand.Rand
+}
+
+// ChaosSource wraps another Source and randomly injects latency, errors,
+// and corrupted package hashes according to Config, so that an installer's
+// retry and fallback logic can be exercised by automated tests instead of
+// only during real outages.
+//
+// A ChaosSource is not safe for concurrent use, because the underlying
+// *rand.Rand isn't; wrap a separate ChaosSource per goroutine if providers
+// are being resolved concurrently.
+type ChaosSource struct {
+	Wrapped Source
+	Config  ChaosConfig
+
+	rnd *rand.Rand
+}
+
+// NewChaosSource constructs a ChaosSource that injects failures into
+// requests to wrapped according to config.
+func NewChaosSource(wrapped Source, config ChaosConfig) *ChaosSource {
+	rnd := config.Rand
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &ChaosSource{
+		Wrapped: wrapped,
+		Config:  config,
+		rnd:     rnd,
+	}
+}
+
+var _ Source = (*ChaosSource)(nil)
+
+// AvailableVersions delegates to Wrapped, subject to injected latency and a
+// chance of synthetic failure.
+func (s *ChaosSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	s.delay()
+	if s.shouldFail() {
+		return nil, fmt.Errorf("chaos: injected failure for AvailableVersions(%s)", provider)
+	}
+	return s.Wrapped.AvailableVersions(provider)
+}
+
+// PackageMeta delegates to Wrapped, subject to injected latency, a chance
+// of synthetic failure, and a chance of the resulting package's advertised
+// hash being corrupted.
+func (s *ChaosSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	s.delay()
+	if s.shouldFail() {
+		return PackageMeta{}, fmt.Errorf("chaos: injected failure for PackageMeta(%s %s %s)", provider, version, target)
+	}
+
+	meta, err := s.Wrapped.PackageMeta(provider, version, target)
+	if err != nil {
+		return meta, err
+	}
+	if s.Config.CorruptHashRate > 0 && s.rnd.Float64() < s.Config.CorruptHashRate {
+		meta.SHA256Sum[0] ^= 0xFF
+	}
+	return meta, nil
+}
+
+func (s *ChaosSource) delay() {
+	if s.Config.Latency > 0 {
+		time.Sleep(s.Config.Latency)
+	}
+}
+
+func (s *ChaosSource) shouldFail() bool {
+	return s.Config.ErrorRate > 0 && s.rnd.Float64() < s.Config.ErrorRate
+}
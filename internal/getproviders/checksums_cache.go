@@ -0,0 +1,118 @@
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// ChecksumsCache fetches and remembers the SHA256SUMS document and its
+// detached signature for a provider release, keyed by provider and version,
+// so that resolving several platforms of the same release within a single
+// run -- the ordinary case for a multi-platform lock operation -- downloads
+// each of the two files only once rather than once per platform.
+//
+// The zero value is ready to use, with requests made using
+// http.DefaultClient; use NewChecksumsCache to supply a different client. A
+// ChecksumsCache is safe for concurrent use by multiple goroutines.
+type ChecksumsCache struct {
+	// HTTPClient is used to fetch documents that aren't already cached. If
+	// nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	mu    sync.Mutex
+	cache map[checksumsCacheKey]*checksumsCacheEntry
+}
+
+type checksumsCacheKey struct {
+	provider addrs.Provider
+	version  string
+}
+
+type checksumsCacheEntry struct {
+	mu                  sync.Mutex
+	done                bool
+	document, signature []byte
+}
+
+// NewChecksumsCache returns a ChecksumsCache that fetches using client, or
+// http.DefaultClient if client is nil.
+func NewChecksumsCache(client *http.Client) *ChecksumsCache {
+	return &ChecksumsCache{HTTPClient: client}
+}
+
+// Get returns the SHA256SUMS document and its detached signature for the
+// release of provider at version whose URLs are documentURL and
+// signatureURL.
+//
+// The first successful call for a given provider and version downloads both
+// files and remembers the result; every later call for the same provider
+// and version, even one passing different URLs, returns that remembered
+// result without making any further request -- provider and version are
+// assumed to uniquely identify a single release's checksums, which is what
+// the registry protocol intends by scoping both files to a release rather
+// than to an individual platform.
+//
+// A failed fetch is not remembered: it's reported to this call's caller,
+// but the next call for the same provider and version retries from
+// scratch, rather than a single transient error (a dropped connection
+// fetching the first platform's checksums, say) permanently failing every
+// later platform lookup for that release for the lifetime of the cache.
+func (c *ChecksumsCache) Get(provider addrs.Provider, version Version, documentURL, signatureURL string) (document, signature []byte, err error) {
+	key := checksumsCacheKey{provider: provider, version: version.String()}
+
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = make(map[checksumsCacheKey]*checksumsCacheEntry)
+	}
+	entry, ok := c.cache[key]
+	if !ok {
+		entry = &checksumsCacheEntry{}
+		c.cache[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.done {
+		return entry.document, entry.signature, nil
+	}
+
+	document, err = c.fetch(documentURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err = c.fetch(signatureURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entry.document, entry.signature, entry.done = document, signature, true
+	return entry.document, entry.signature, nil
+}
+
+func (c *ChecksumsCache) fetch(url string) ([]byte, error) {
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s fetching %s", resp.Status, url)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", url, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,153 @@
+package getproviders
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestChecksumsCacheGetDedupesByProviderVersion(t *testing.T) {
+	var documentHits, signatureHits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/SHA256SUMS":
+			atomic.AddInt32(&documentHits, 1)
+			w.Write([]byte("deadbeef  terraform-provider-foo_1.0.0_linux_amd64.zip\n"))
+		case "/SHA256SUMS.sig":
+			atomic.AddInt32(&signatureHits, 1)
+			w.Write([]byte("fake signature"))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	cache := NewChecksumsCache(server.Client())
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "foo")
+	version := versionMust(t, "1.0.0")
+
+	for i := 0; i < 3; i++ {
+		document, signature, err := cache.Get(provider, version, server.URL+"/SHA256SUMS", server.URL+"/SHA256SUMS.sig")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(document) == "" || string(signature) == "" {
+			t.Fatalf("got empty document or signature on call %d", i)
+		}
+	}
+
+	if got := atomic.LoadInt32(&documentHits); got != 1 {
+		t.Errorf("wrong number of document fetches: got %d, want 1", got)
+	}
+	if got := atomic.LoadInt32(&signatureHits); got != 1 {
+		t.Errorf("wrong number of signature fetches: got %d, want 1", got)
+	}
+}
+
+func TestChecksumsCacheGetDistinguishesVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("content for " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	cache := NewChecksumsCache(server.Client())
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "foo")
+
+	doc1, _, err := cache.Get(provider, versionMust(t, "1.0.0"), server.URL+"/1.0.0/SUMS", server.URL+"/1.0.0/SUMS.sig")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	doc2, _, err := cache.Get(provider, versionMust(t, "2.0.0"), server.URL+"/2.0.0/SUMS", server.URL+"/2.0.0/SUMS.sig")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(doc1) == string(doc2) {
+		t.Errorf("expected different content for different versions, got the same: %q", doc1)
+	}
+}
+
+func TestChecksumsCacheGetRetriesAfterFailure(t *testing.T) {
+	var documentHits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/SHA256SUMS":
+			if atomic.AddInt32(&documentHits, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte("deadbeef  terraform-provider-foo_1.0.0_linux_amd64.zip\n"))
+		case "/SHA256SUMS.sig":
+			w.Write([]byte("fake signature"))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	cache := NewChecksumsCache(server.Client())
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "foo")
+	version := versionMust(t, "1.0.0")
+
+	if _, _, err := cache.Get(provider, version, server.URL+"/SHA256SUMS", server.URL+"/SHA256SUMS.sig"); err == nil {
+		t.Fatalf("expected an error from the first, failing fetch")
+	}
+
+	document, signature, err := cache.Get(provider, version, server.URL+"/SHA256SUMS", server.URL+"/SHA256SUMS.sig")
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %s", err)
+	}
+	if string(document) == "" || string(signature) == "" {
+		t.Fatalf("got empty document or signature on retry")
+	}
+	if got := atomic.LoadInt32(&documentHits); got != 2 {
+		t.Errorf("wrong number of document fetches: got %d, want 2 (the failure should not have been cached)", got)
+	}
+}
+
+func TestRegistryClientPackageMetaChecksumsURLs(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"protocols": ["5.0"],
+			"os": "linux",
+			"arch": "amd64",
+			"filename": "terraform-provider-foo_1.0.0_linux_amd64.zip",
+			"download_url": "/pkg/terraform-provider-foo_1.0.0_linux_amd64.zip",
+			"shasum": "000000000000000000000000000000000000000000000000000000000000f00d",
+			"shasums_url": "/pkg/terraform-provider-foo_1.0.0_SHA256SUMS",
+			"shasums_signature_url": "/pkg/terraform-provider-foo_1.0.0_SHA256SUMS.sig",
+			"signing_keys": {"gpg_public_keys": [{"ascii_armor": "fake-key"}]}
+		}`)
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	baseURL, err := url.Parse(serverURL + "/")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	client := newRegistryClient(baseURL, nil)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "foo")
+
+	meta, err := client.PackageMeta(provider, versionMust(t, "1.0.0"), Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := serverURL + "/pkg/terraform-provider-foo_1.0.0_SHA256SUMS"; meta.ChecksumsSHA256SumsURL != want {
+		t.Errorf("wrong ChecksumsSHA256SumsURL: got %q, want %q", meta.ChecksumsSHA256SumsURL, want)
+	}
+	if want := serverURL + "/pkg/terraform-provider-foo_1.0.0_SHA256SUMS.sig"; meta.ChecksumsSignatureURL != want {
+		t.Errorf("wrong ChecksumsSignatureURL: got %q, want %q", meta.ChecksumsSignatureURL, want)
+	}
+	if len(meta.SigningKeys) != 1 || meta.SigningKeys[0] != "fake-key" {
+		t.Errorf("wrong SigningKeys: got %#v", meta.SigningKeys)
+	}
+}
@@ -0,0 +1,73 @@
+package getproviders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RemovedProvider describes a single provider package that was deleted by
+// CleanupOrphanedProviders.
+type RemovedProvider struct {
+	Installed
+	Bytes int64
+}
+
+// CleanupOrphanedProviders removes any installed provider packages under
+// baseDir that are not referenced by the given lock file, returning a
+// description of each package it removed.
+//
+// baseDir is expected to follow the directory layout understood by
+// SearchInstalledProviders. A provider package is considered orphaned if
+// the lock file has no entry at all for its provider address, or if it has
+// an entry but for a different version.
+//
+// This is intended for use as the implementation of an opt-in cleanup step
+// run after dependency changes, so that old provider versions do not linger
+// under .terraform/providers forever. It does not touch anything that is
+// still referenced by the lock file, even if other platforms' packages for
+// the same provider and version have already been removed.
+func CleanupOrphanedProviders(baseDir string, lock *LockFile) ([]RemovedProvider, error) {
+	installed, err := SearchInstalledProviders(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []RemovedProvider
+	for _, inst := range installed {
+		if lock.HasProviderVersion(inst.Provider, inst.Version) {
+			continue
+		}
+
+		size, err := dirSize(inst.PackageDir)
+		if err != nil {
+			return removed, fmt.Errorf("failed to measure %s before removal: %s", inst.PackageDir, err)
+		}
+		if err := os.RemoveAll(inst.PackageDir); err != nil {
+			return removed, fmt.Errorf("failed to remove orphaned provider package %s: %s", inst.PackageDir, err)
+		}
+
+		removed = append(removed, RemovedProvider{
+			Installed: inst,
+			Bytes:     size,
+		})
+	}
+
+	return removed, nil
+}
+
+// dirSize returns the total size in bytes of all of the regular files
+// found anywhere under the given directory.
+func dirSize(dir string) (int64, error) {
+	var total int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,31 @@
+package getproviders
+
+import "time"
+
+// DefaultSignedURLSkewTolerance is the default value of
+// SignedURLSkewTolerance.
+const DefaultSignedURLSkewTolerance = 30 * time.Second
+
+// SignedURLSkewTolerance is the amount of apparent clock skew that
+// IsSignedURLExpired tolerates between the local clock and whatever clock
+// produced a signed URL's expiry time, before treating the URL as actually
+// expired.
+//
+// A registry or mirror that hands out pre-signed download URLs (such as a
+// cloud storage bucket's time-limited object URL) sets their expiry using
+// its own clock, which is very rarely in perfect agreement with the local
+// one; without some tolerance, an ordinarily-small amount of skew causes
+// otherwise-valid downloads to fail right at the edge of a URL's validity
+// window, with an error that looks identical to one that's genuinely
+// expired. This is package-global, in the same way as DefaultRegistryHost,
+// so that a caller who needs a stricter or looser policy can adjust it once
+// rather than threading a parameter through every download path.
+var SignedURLSkewTolerance = DefaultSignedURLSkewTolerance
+
+// IsSignedURLExpired reports whether a signed URL whose signature is
+// documented to expire at expires should be treated as expired as of now,
+// tolerating up to SignedURLSkewTolerance worth of disagreement between the
+// two clocks before actually calling it expired.
+func IsSignedURLExpired(expires, now time.Time) bool {
+	return now.Sub(expires) > SignedURLSkewTolerance
+}
@@ -0,0 +1,93 @@
+package getproviders
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDescribeTLSErrorClockSkew(t *testing.T) {
+	notBefore := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notAfter}
+
+	tests := map[string]struct {
+		now  time.Time
+		want string
+	}{
+		"local clock behind": {
+			now:  notBefore.Add(-2 * time.Hour),
+			want: "behind",
+		},
+		"local clock ahead": {
+			now:  notAfter.Add(3 * time.Hour),
+			want: "ahead",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			skew, desc, ok := certClockSkew(cert, test.now)
+			if !ok {
+				t.Fatalf("certClockSkew reported no skew for a certificate the given time falls outside of")
+			}
+			if desc != test.want {
+				t.Errorf("wrong direction: got %q, want %q", desc, test.want)
+			}
+			if skew <= 0 {
+				t.Errorf("expected a positive skew duration, got %s", skew)
+			}
+		})
+	}
+
+	if _, _, ok := certClockSkew(cert, notBefore.Add(time.Hour)); ok {
+		t.Errorf("certClockSkew reported skew for a time within the certificate's validity window")
+	}
+
+	err := x509.CertificateInvalidError{Cert: cert, Reason: x509.Expired}
+	got := tlsErrorHint(err)
+	if !contains(got, "local system clock") {
+		t.Errorf("tlsErrorHint didn't mention clock skew: %s", got)
+	}
+}
+
+func TestDescribeTLSErrorOther(t *testing.T) {
+	err := errors.New("some other error")
+	if got := tlsErrorHint(err); got != "" {
+		t.Errorf("wrong result: got %q, want empty string for an unrecognized error", got)
+	}
+}
+
+func contains(haystack, needle string) bool {
+	return len(haystack) >= len(needle) && (func() bool {
+		for i := 0; i+len(needle) <= len(haystack); i++ {
+			if haystack[i:i+len(needle)] == needle {
+				return true
+			}
+		}
+		return false
+	})()
+}
+
+func TestIsSignedURLExpired(t *testing.T) {
+	expires := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		now  time.Time
+		want bool
+	}{
+		"well before expiry":     {expires.Add(-time.Hour), false},
+		"exactly at expiry":      {expires, false},
+		"within tolerance after": {expires.Add(SignedURLSkewTolerance / 2), false},
+		"well beyond tolerance":  {expires.Add(SignedURLSkewTolerance * 10), true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsSignedURLExpired(expires, test.now); got != test.want {
+				t.Errorf("IsSignedURLExpired(%s, %s) = %v, want %v", expires, test.now, got, test.want)
+			}
+		})
+	}
+}
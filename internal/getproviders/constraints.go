@@ -0,0 +1,93 @@
+package getproviders
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apparentlymart/go-versions/versions"
+	"github.com/apparentlymart/go-versions/versions/constraints"
+)
+
+// VersionConstraints represents a parsed set of version constraints using
+// the same Ruby-style syntax Terraform's configuration language accepts in
+// a required_providers block (e.g. "~> 1.0", ">= 1.0, < 2.0").
+//
+// It's exported from this package, rather than kept as an implementation
+// detail of constraint checking, so that registry servers and policy tools
+// that need to apply Terraform's exact selection semantics can parse and
+// normalize constraint strings the same way Terraform itself does, instead
+// of risking a subtly different reimplementation.
+type VersionConstraints = constraints.IntersectionSpec
+
+// ParseVersionConstraints parses a comma-separated, Ruby-style version
+// constraint string -- the same syntax accepted for a provider's
+// version argument in configuration -- into a VersionConstraints value.
+func ParseVersionConstraints(str string) (VersionConstraints, error) {
+	return constraints.ParseRubyStyleMulti(str)
+}
+
+// NormalizeVersionConstraints parses str and renders it back out in its
+// canonical pretty-printed form -- collapsing redundant whitespace and
+// using one consistent spelling per operator -- so that two constraint
+// strings that mean the same thing also compare equal as text.
+func NormalizeVersionConstraints(str string) (string, error) {
+	spec, err := ParseVersionConstraints(str)
+	if err != nil {
+		return "", err
+	}
+	return FormatVersionConstraints(spec), nil
+}
+
+// FormatVersionConstraints renders spec back out in Terraform's canonical
+// Ruby-style syntax, the inverse of ParseVersionConstraints.
+func FormatVersionConstraints(spec VersionConstraints) string {
+	parts := make([]string, len(spec))
+	for i, selection := range spec {
+		parts[i] = formatSelectionSpec(selection)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatSelectionSpec(selection constraints.SelectionSpec) string {
+	// Ruby-style syntax has no wildcards, so unconstrained trailing segments
+	// are omitted rather than rendered as "*" the way VersionSpec.String
+	// would render them.
+	b := selection.Boundary
+	boundary := b.Major.String()
+	if !b.Minor.Unconstrained {
+		boundary += "." + b.Minor.String()
+		if !b.Patch.Unconstrained {
+			boundary += "." + b.Patch.String()
+		}
+	}
+	if b.Prerelease != "" {
+		boundary += "-" + b.Prerelease
+	}
+	if b.Metadata != "" {
+		boundary += "+" + b.Metadata
+	}
+	switch selection.Operator {
+	case constraints.OpGreaterThanOrEqualMinorOnly, constraints.OpGreaterThanOrEqualPatchOnly:
+		return fmt.Sprintf("~> %s", boundary)
+	case constraints.OpEqual, constraints.OpMatch, constraints.OpUnconstrained:
+		return boundary
+	case constraints.OpNotEqual:
+		return fmt.Sprintf("!= %s", boundary)
+	case constraints.OpGreaterThan:
+		return fmt.Sprintf("> %s", boundary)
+	case constraints.OpGreaterThanOrEqual:
+		return fmt.Sprintf(">= %s", boundary)
+	case constraints.OpLessThan:
+		return fmt.Sprintf("< %s", boundary)
+	case constraints.OpLessThanOrEqual:
+		return fmt.Sprintf("<= %s", boundary)
+	default:
+		return fmt.Sprintf("%s %s", selection.Operator, boundary)
+	}
+}
+
+// MeetingVersionConstraints returns the set of versions that satisfy spec,
+// suitable for testing individual versions against with Set.Has.
+func MeetingVersionConstraints(spec VersionConstraints) versions.Set {
+	return versions.MeetingConstraints(spec)
+}
@@ -0,0 +1,152 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// debugRecordingSensitiveHeaders lists the request header names that
+// DebugRecording redacts before writing an exchange out, since these
+// routinely carry registry or mirror credentials.
+var debugRecordingSensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+}
+
+// DebugRecording accumulates a HAR-like log of the HTTP exchanges made
+// while installing providers, with credentials redacted from both headers
+// and URL query strings, so that a user can attach it as reproducible
+// evidence to a bug report about a misbehaving private registry or mirror
+// without also handing out a live credential or signed download URL.
+//
+// It intentionally does not record request or response bodies: provider
+// package downloads are large binary archives that wouldn't be useful
+// inline in a debug log, and recording arbitrary registry API response
+// bodies risks capturing more than the credentials-only redaction accounts
+// for. The method, URL, status, and headers of each exchange are usually
+// enough to diagnose the kinds of protocol and connectivity problems this
+// is meant to help with.
+type DebugRecording struct {
+	mu      sync.Mutex
+	path    string
+	entries []debugRecordingEntry
+}
+
+type debugRecordingEntry struct {
+	StartedAt  time.Time                   `json:"startedAt"`
+	DurationMS int64                       `json:"durationMS"`
+	Request    debugRecordingExchangeSide  `json:"request"`
+	Response   *debugRecordingExchangeSide `json:"response,omitempty"`
+	Error      string                      `json:"error,omitempty"`
+}
+
+type debugRecordingExchangeSide struct {
+	Method     string              `json:"method,omitempty"`
+	URL        string              `json:"url,omitempty"`
+	StatusCode int                 `json:"statusCode,omitempty"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+}
+
+// NewDebugRecording creates a DebugRecording that will write its exchanges
+// to path, as indented JSON, whenever Save is called.
+func NewDebugRecording(path string) *DebugRecording {
+	return &DebugRecording{path: path}
+}
+
+// Transport wraps inner in a http.RoundTripper that appends a redacted
+// record of every request/response pair it handles to r before delegating
+// to inner.
+func (r *DebugRecording) Transport(inner http.RoundTripper) http.RoundTripper {
+	return &debugRecordingRoundTripper{recording: r, inner: inner}
+}
+
+// Save writes the exchanges recorded so far to the configured path.
+func (r *DebugRecording) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(struct {
+		Entries []debugRecordingEntry `json:"entries"`
+	}{r.entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode debug recording: %s", err)
+	}
+	if err := ioutil.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write debug recording to %s: %s", r.path, err)
+	}
+	return nil
+}
+
+func (r *DebugRecording) record(entry debugRecordingEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+type debugRecordingRoundTripper struct {
+	recording *DebugRecording
+	inner     http.RoundTripper
+}
+
+func (t *debugRecordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := debugRecordingEntry{
+		StartedAt: time.Now(),
+		Request: debugRecordingExchangeSide{
+			Method:  req.Method,
+			URL:     redactDebugRecordingURL(req.URL),
+			Headers: redactDebugRecordingHeaders(req.Header),
+		},
+	}
+
+	start := time.Now()
+	resp, err := t.inner.RoundTrip(req)
+	entry.DurationMS = time.Since(start).Milliseconds()
+
+	if err != nil {
+		entry.Error = err.Error()
+		t.recording.record(entry)
+		return resp, err
+	}
+
+	entry.Response = &debugRecordingExchangeSide{
+		StatusCode: resp.StatusCode,
+		Headers:    redactDebugRecordingHeaders(resp.Header),
+	}
+	t.recording.record(entry)
+	return resp, nil
+}
+
+// redactDebugRecordingURL returns u's string form with its query string
+// redacted, since several sources (a cloud storage bucket's pre-signed
+// download URL, for instance) carry their credential as a signature embedded
+// in the query string rather than in a header, and redactDebugRecordingHeaders
+// has no way to catch that. A query string is dropped wholesale rather than
+// parameter-by-parameter against a list of known signing parameter names,
+// since a list like that would need to keep up with every source this
+// package adds and silently under-redact for one it doesn't yet know about.
+func redactDebugRecordingURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	redacted := *u
+	redacted.RawQuery = "(redacted)"
+	return redacted.String()
+}
+
+func redactDebugRecordingHeaders(headers http.Header) map[string][]string {
+	redacted := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		if debugRecordingSensitiveHeaders[strings.ToLower(name)] {
+			redacted[name] = []string{"(redacted)"}
+			continue
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
@@ -0,0 +1,56 @@
+package getproviders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDebugRecordingRedactsSignedURLQueryString(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recording := NewDebugRecording("")
+	client := &http.Client{Transport: recording.Transport(http.DefaultTransport)}
+
+	req, err := http.NewRequest("GET", server.URL+"/package.zip?X-Amz-Signature=topsecret&X-Amz-Credential=AKIAEXAMPLE", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	if len(recording.entries) != 1 {
+		t.Fatalf("wrong number of recorded entries: got %d, want 1", len(recording.entries))
+	}
+	got := recording.entries[0].Request.URL
+	if got != server.URL+"/package.zip?(redacted)" {
+		t.Errorf("wrong recorded URL: got %q", got)
+	}
+}
+
+func TestDebugRecordingLeavesUnsignedURLAlone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recording := NewDebugRecording("")
+	client := &http.Client{Transport: recording.Transport(http.DefaultTransport)}
+
+	resp, err := client.Get(server.URL + "/v1/providers/hashicorp/aws/versions")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	got := recording.entries[0].Request.URL
+	if got != server.URL+"/v1/providers/hashicorp/aws/versions" {
+		t.Errorf("wrong recorded URL: got %q", got)
+	}
+}
@@ -0,0 +1,20 @@
+// +build !darwin,!linux
+
+package getproviders
+
+import "fmt"
+
+// DetectCurrentPlatform returns the Platform Terraform should treat as "the
+// platform this process is running on" when installing providers for local
+// use, along with a short explanation of how it arrived at that answer.
+//
+// The explanation exists so that callers can log it: a surprising mismatch
+// between the detected platform and what a user expects -- emulation,
+// WSL, a 32-bit userland on a 64-bit kernel -- is one of the most
+// confusing "no package available for this platform" failure modes to
+// diagnose without it. On operating systems without a more specific
+// detection rule, the answer is always just the Go runtime's own GOOS and
+// GOARCH.
+func DetectCurrentPlatform() (Platform, string) {
+	return CurrentPlatform, fmt.Sprintf("using the Go runtime's reported platform (%s)", CurrentPlatform)
+}
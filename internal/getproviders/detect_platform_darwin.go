@@ -0,0 +1,44 @@
+// +build darwin
+
+package getproviders
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// DetectCurrentPlatform returns CurrentPlatform unless this process appears
+// to be running under Rosetta 2 emulation on Apple Silicon, in which case
+// it returns the native arm64 platform instead and explains why.
+//
+// A Terraform binary launched under Rosetta reports itself as darwin_amd64
+// even though the host is actually darwin_arm64, which leads installers to
+// fetch an emulated amd64 provider when a faster native arm64 one is
+// available. We detect the emulation the same way Apple's own tooling
+// does: the sysctl.proc_translated sysctl reads 1 for a translated
+// process and is absent (an error) on Intel Macs and older macOS versions
+// that predate Rosetta 2.
+func DetectCurrentPlatform() (Platform, string) {
+	defaultReason := fmt.Sprintf("using the Go runtime's reported platform (%s)", CurrentPlatform)
+	if CurrentPlatform.Arch != "amd64" {
+		return CurrentPlatform, defaultReason
+	}
+
+	out, err := exec.Command("sysctl", "-n", "sysctl.proc_translated").Output()
+	if err != nil {
+		// Most likely this sysctl doesn't exist at all, meaning we're
+		// definitely not running under Rosetta.
+		return CurrentPlatform, defaultReason
+	}
+
+	if strings.TrimSpace(string(out)) != "1" {
+		return CurrentPlatform, defaultReason
+	}
+
+	native := Platform{OS: "darwin", Arch: "arm64"}
+	return native, fmt.Sprintf(
+		"this process is running under Rosetta 2 emulation (reported platform %s); using the native platform %s instead",
+		CurrentPlatform, native,
+	)
+}
@@ -0,0 +1,49 @@
+// +build linux
+
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+)
+
+// DetectCurrentPlatform returns CurrentPlatform along with an explanation
+// of how it was derived, calling out two situations on Linux that are easy
+// to mistake for a plain architecture mismatch: running inside Windows
+// Subsystem for Linux, and running a 32-bit userland on top of a 64-bit
+// kernel.
+//
+// Neither situation changes the returned Platform -- a 32-bit WSL process
+// genuinely needs a 32-bit provider build, just like it would on bare
+// metal -- but both are common causes of a user being confused about which
+// platform a "no package available for this platform" error is even
+// talking about, so we call them out in the returned reasoning for logging.
+func DetectCurrentPlatform() (Platform, string) {
+	var notes []string
+
+	if kernelVersion, err := ioutil.ReadFile("/proc/version"); err == nil {
+		if strings.Contains(strings.ToLower(string(kernelVersion)), "microsoft") {
+			notes = append(notes, "detected Windows Subsystem for Linux from /proc/version")
+		}
+	}
+
+	if CurrentPlatform.Arch == "386" {
+		if kernelArch, err := exec.Command("uname", "-m").Output(); err == nil {
+			switch strings.TrimSpace(string(kernelArch)) {
+			case "x86_64", "amd64", "aarch64", "arm64":
+				notes = append(notes, fmt.Sprintf("detected a 32-bit userland on a 64-bit kernel (uname -m reported %q)", strings.TrimSpace(string(kernelArch))))
+			}
+		}
+	}
+
+	if len(notes) == 0 {
+		return CurrentPlatform, fmt.Sprintf("using the Go runtime's reported platform (%s)", CurrentPlatform)
+	}
+
+	return CurrentPlatform, fmt.Sprintf(
+		"using the Go runtime's reported platform (%s); %s",
+		CurrentPlatform, strings.Join(notes, "; "),
+	)
+}
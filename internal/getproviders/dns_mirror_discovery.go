@@ -0,0 +1,75 @@
+package getproviders
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// dnsMirrorSRVService is the SRV service name, following the usual
+// "_service._proto" convention (RFC 2782), under which a network mirror
+// advertises itself in DNS.
+const dnsMirrorSRVService = "_terraform-provider-mirror._tcp"
+
+// ErrMirrorNotAdvertised is an error type used to indicate that a domain
+// given to DiscoverMirrorURLFromDNS has no SRV record advertising a
+// provider mirror.
+type ErrMirrorNotAdvertised struct {
+	Domain string
+}
+
+func (err ErrMirrorNotAdvertised) Error() string {
+	return fmt.Sprintf("no provider mirror is advertised in DNS for %s", err.Domain)
+}
+
+// DiscoverMirrorURLFromDNS looks up a network mirror's base URL from SRV
+// (and, for attributes an SRV record can't carry, TXT) records published
+// under domain, so that a machine can be configured with just a DNS domain
+// name and automatically pick up whichever mirror is nearest to wherever
+// it's currently connected, without any local configuration change.
+//
+// The SRV record at "_terraform-provider-mirror._tcp.<domain>" supplies the
+// mirror's host and port. A TXT record at that same name may additionally
+// carry whitespace-separated "key=value" attributes: "proto=http" selects
+// the http scheme instead of the default https, and "path=/some/prefix"
+// sets the URL path (which defaults to "/").
+//
+// If domain has no such SRV record, this returns an ErrMirrorNotAdvertised
+// error so that callers can fall back to their usual static mirror
+// configuration.
+func DiscoverMirrorURLFromDNS(domain string) (*url.URL, error) {
+	_, targets, err := net.LookupSRV("terraform-provider-mirror", "tcp", domain)
+	if err != nil || len(targets) == 0 {
+		return nil, ErrMirrorNotAdvertised{Domain: domain}
+	}
+
+	// net.LookupSRV already returns results ordered by priority and then
+	// weight, so the first one is our preferred mirror.
+	target := targets[0]
+
+	scheme := "https"
+	path := "/"
+	if txtRecords, err := net.LookupTXT(dnsMirrorSRVService + "." + domain); err == nil {
+		for _, txt := range txtRecords {
+			for _, attr := range strings.Fields(txt) {
+				parts := strings.SplitN(attr, "=", 2)
+				if len(parts) != 2 {
+					continue
+				}
+				switch parts[0] {
+				case "proto":
+					scheme = parts[1]
+				case "path":
+					path = parts[1]
+				}
+			}
+		}
+	}
+
+	return &url.URL{
+		Scheme: scheme,
+		Host:   fmt.Sprintf("%s:%d", strings.TrimSuffix(target.Target, "."), target.Port),
+		Path:   path,
+	}, nil
+}
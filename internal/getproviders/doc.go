@@ -4,8 +4,20 @@
 // the distribution archive for a specific version of a specific provider
 // targeting a particular platform.
 //
-// This package is not responsible for choosing the best version to install
-// from a set of available versions, or for any signature verification of the
-// archives it fetches. Callers will use this package in conjunction with other
-// logic elsewhere in order to construct a full provider installer.
+// Checksum and signature verification of the archives it fetches is
+// provided as PackageAuthentication checks, which callers apply themselves.
+// Callers will use this package in conjunction with
+// other logic elsewhere in order to construct a full provider installer.
+//
+// ResolvePackages, ResolveBatch, and the override, mirror-scanning, SBOM,
+// quarantine, and telemetry helpers in this package are exercised today only
+// by this package's own tests and by each other (for example,
+// ResolveBatch's use of ResolvePackages). The real terraform init installer
+// in internal/providercache does not yet call into any of them: as of this
+// writing only NewRegistrySource, ParsePlatform, ParseVersion,
+// PublishArtifact, PublishRelease, and SetDefaultRegistryHost have callers
+// outside this package. Wiring a
+// feature in this package into an actual CLI command needs a deliberate,
+// separately-reviewed integration with internal/providercache, not an
+// assumption that adding it here is sufficient on its own.
 package getproviders
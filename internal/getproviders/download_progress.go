@@ -0,0 +1,127 @@
+package getproviders
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	isatty "github.com/mattn/go-isatty"
+)
+
+// FetchProgress reports how much of a package download has completed so
+// far, for rendering as a progress bar or periodic status line.
+type FetchProgress struct {
+	URL string
+
+	// BytesRead is the number of bytes transferred so far.
+	BytesRead int64
+
+	// TotalBytes is the total size of the download, or -1 if the server
+	// didn't report a Content-Length and so the total is unknown.
+	TotalBytes int64
+}
+
+// progressReader wraps an io.Reader, calling onProgress after each read
+// with the cumulative byte count, so that DownloadPackageToTempFile can
+// report FetchProgress events without its caller needing to intercept the
+// transfer itself.
+type progressReader struct {
+	io.Reader
+	url        string
+	total      int64
+	read       int64
+	onProgress func(FetchProgress)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += int64(n)
+	if n > 0 && r.onProgress != nil {
+		r.onProgress(FetchProgress{URL: r.url, BytesRead: r.read, TotalBytes: r.total})
+	}
+	return n, err
+}
+
+// NewProgressWriter returns a FetchProgress callback that renders progress
+// to out: a redrawing progress bar with a transferred/total byte count and
+// an ETA when out is a terminal, or a throttled sequence of plain status
+// lines -- one roughly every second, plus a final one -- otherwise, since a
+// redrawing bar is meaningless once written to a log file that can't move
+// its cursor back up.
+//
+// The returned callback is not safe for concurrent use; each concurrent
+// download should get its own.
+func NewProgressWriter(out *os.File) func(FetchProgress) {
+	isTTY := isatty.IsTerminal(out.Fd())
+	var last time.Time
+	var startedAt time.Time
+
+	return func(p FetchProgress) {
+		now := time.Now()
+		if startedAt.IsZero() {
+			startedAt = now
+		}
+
+		if isTTY {
+			fmt.Fprintf(out, "\r%s%s", formatProgressLine(p, now.Sub(startedAt)), clearToEOL)
+			if p.TotalBytes >= 0 && p.BytesRead >= p.TotalBytes {
+				fmt.Fprintln(out)
+			}
+			return
+		}
+
+		// Non-TTY output can't redraw a line, so only print at most once a
+		// second, plus unconditionally for the final update, to avoid
+		// flooding a CI log with one line per chunk read.
+		final := p.TotalBytes >= 0 && p.BytesRead >= p.TotalBytes
+		if !final && now.Sub(last) < time.Second {
+			return
+		}
+		last = now
+		fmt.Fprintln(out, formatProgressLine(p, now.Sub(startedAt)))
+	}
+}
+
+// clearToEOL is the ANSI escape sequence to clear from the cursor to the
+// end of the line, used to erase any leftover characters from a longer
+// previous line when redrawing a shorter one.
+const clearToEOL = "\x1b[K"
+
+func formatProgressLine(p FetchProgress, elapsed time.Duration) string {
+	if p.TotalBytes < 0 {
+		return fmt.Sprintf("%s ... %s", p.URL, formatBytes(p.BytesRead))
+	}
+
+	percent := 0
+	if p.TotalBytes > 0 {
+		percent = int(100 * p.BytesRead / p.TotalBytes)
+	}
+
+	eta := "unknown"
+	if p.BytesRead > 0 && elapsed > 0 {
+		rate := float64(p.BytesRead) / elapsed.Seconds()
+		if rate > 0 {
+			remaining := float64(p.TotalBytes-p.BytesRead) / rate
+			eta = (time.Duration(remaining) * time.Second).String()
+		}
+	}
+
+	return fmt.Sprintf(
+		"%s ... %3d%% (%s/%s) ETA %s",
+		p.URL, percent, formatBytes(p.BytesRead), formatBytes(p.TotalBytes), eta,
+	)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,53 @@
+package getproviders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestDownloadPackageToTempFileReportsProgress(t *testing.T) {
+	const body = "pretend provider binary contents"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	var events []FetchProgress
+	path, err := DownloadPackageToTempFile(nil, server.URL, DownloadConfig{
+		OnProgress: func(p FetchProgress) {
+			events = append(events, p)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.Remove(path)
+
+	if len(events) == 0 {
+		t.Fatalf("expected at least one progress event")
+	}
+	last := events[len(events)-1]
+	if last.BytesRead != int64(len(body)) {
+		t.Errorf("wrong final byte count: got %d, want %d", last.BytesRead, len(body))
+	}
+	if last.TotalBytes != int64(len(body)) {
+		t.Errorf("wrong total byte count: got %d, want %d", last.TotalBytes, len(body))
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:         "0 B",
+		1023:      "1023 B",
+		1024:      "1.0 KiB",
+		1048576:   "1.0 MiB",
+		104857600: "100.0 MiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package getproviders
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+)
+
+// DownloadConfig configures where and how temporary files are created
+// while downloading a provider package, so that an embedder running on a
+// multi-user build host can point downloads at a private directory rather
+// than the system default temp directory, which is usually shared and
+// world-readable.
+type DownloadConfig struct {
+	// TempDir is the directory temporary download files are created in. An
+	// empty TempDir uses the operating system's default temporary
+	// directory, via os.TempDir.
+	TempDir string
+
+	// OnProgress, if non-nil, is called repeatedly as the download
+	// progresses, reporting how many bytes have been transferred so far and,
+	// if known, the total size. NewProgressWriter constructs a callback
+	// suitable for this field that renders a TTY progress bar or periodic
+	// status lines depending on where it's writing to.
+	OnProgress func(FetchProgress)
+}
+
+// DownloadPackageToTempFile downloads the content at url into a new
+// temporary file under config.TempDir (or the system default if unset).
+//
+// The temp directory, if it doesn't already exist, is created with 0700
+// permissions, and the downloaded file itself with 0600, so that other
+// users on a shared build host can neither read an in-progress download
+// nor swap in a substitute file before verification completes.
+//
+// The caller is responsible for removing the returned path once it's done
+// with it, typically after moving it into its final location.
+func DownloadPackageToTempFile(client *http.Client, url string, config DownloadConfig) (string, error) {
+	dir := config.TempDir
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", fmt.Errorf("failed to create temporary download directory %s: %s", dir, err)
+		}
+	}
+
+	f, err := ioutil.TempFile(dir, "terraform-provider-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary download file: %s", err)
+	}
+	defer f.Close()
+
+	if err := f.Chmod(0600); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to set secure permissions on %s: %s", f.Name(), err)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to download %s: %s", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to download %s: unexpected status %s", url, resp.Status)
+	}
+
+	body := io.Reader(resp.Body)
+	if config.OnProgress != nil {
+		body = &progressReader{
+			Reader:     resp.Body,
+			url:        url,
+			total:      resp.ContentLength,
+			onProgress: config.OnProgress,
+		}
+	}
+
+	if _, err := io.Copy(f, body); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write downloaded content from %s: %s", url, err)
+	}
+
+	return f.Name(), nil
+}
@@ -0,0 +1,54 @@
+package getproviders
+
+import (
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// EnumerableSource is an optional capability a Source can implement in
+// addition to the required AvailableVersions and PackageMeta methods, for
+// the minority of sources that are able to report everything they have
+// without already being told which providers to ask about.
+//
+// Tools like mirror diffing and inventory reporting, which need to see a
+// source's whole contents rather than resolve a specific set of
+// requirements against it, should type-assert for this interface and fall
+// back to a narrower, per-provider strategy (or report themselves
+// unsupported) when a Source doesn't implement it.
+//
+// Implementing this is only feasible for a source that either enumerates a
+// bounded local resource directly (a filesystem mirror directory) or
+// publishes its own complete listing (a registry or mirror protocol with a
+// discovery endpoint for it); it's deliberately not part of the required
+// Source interface, since sources backed by a per-provider-only remote API
+// have no way to support it at all.
+type EnumerableSource interface {
+	Source
+
+	// AllAvailablePackages returns every provider version the source
+	// currently knows about, without needing the caller to already know
+	// which providers to ask for.
+	AllAvailablePackages() (map[addrs.Provider]VersionList, error)
+}
+
+// AllAvailablePackagesFromSource returns the result of calling
+// AllAvailablePackages on source if it implements EnumerableSource, or an error if it doesn't.
+//
+// This is a convenience for callers, such as mirror diffing or inventory
+// tools, that want to report a clear error for a source that can't be
+// enumerated rather than needing their own type assertion and error
+// message at every call site.
+func AllAvailablePackagesFromSource(source Source) (map[addrs.Provider]VersionList, error) {
+	enumerable, ok := source.(EnumerableSource)
+	if !ok {
+		return nil, unenumerableSourceError{source}
+	}
+	return enumerable.AllAvailablePackages()
+}
+
+type unenumerableSourceError struct {
+	source Source
+}
+
+func (e unenumerableSourceError) Error() string {
+	return "this source does not support enumerating all of its available packages"
+}
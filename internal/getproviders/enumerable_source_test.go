@@ -0,0 +1,46 @@
+package getproviders
+
+import (
+	"testing"
+)
+
+func TestAllAvailablePackagesFromSource(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	source := NewFilesystemMirrorSource(baseDir)
+
+	got, err := AllAvailablePackagesFromSource(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("wrong number of providers: got %d, want 1", len(got))
+	}
+
+	if _, err := AllAvailablePackagesFromSource(MultiSource(nil)); err != nil {
+		t.Fatalf("unexpected error enumerating an empty MultiSource: %s", err)
+	}
+}
+
+func TestMultiSourceAllAvailablePackages(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	multi := MultiSource{
+		{Source: NewFilesystemMirrorSource(baseDir)},
+	}
+
+	got, err := multi.AllAvailablePackages()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("wrong number of providers: got %d, want 1", len(got))
+	}
+	for _, versions := range got {
+		if len(versions) != 2 {
+			t.Errorf("wrong number of versions: got %d, want 2", len(versions))
+		}
+	}
+}
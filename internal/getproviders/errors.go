@@ -2,6 +2,7 @@ package getproviders
 
 import (
 	"fmt"
+	"strings"
 
 	svchost "github.com/hashicorp/terraform-svchost"
 	"github.com/hashicorp/terraform/addrs"
@@ -97,6 +98,36 @@ func (err ErrProviderNotKnown) Error() string {
 	)
 }
 
+// ErrNoSuitableVersion is an error type used to indicate that a provider
+// registry does know about the requested provider, and returned at least one
+// version for it, but none of the versions it returned satisfy the given
+// version constraints.
+//
+// This is distinct from ErrProviderNotKnown, which means the registry has no
+// knowledge of the provider namespace or type at all. Conflating the two
+// would misreport an ordinary "no version matches your constraint" failure
+// as if the provider didn't exist.
+type ErrNoSuitableVersion struct {
+	Provider addrs.Provider
+
+	// Constraints describes the constraints that no available version
+	// could satisfy, for inclusion in the error message. It's optional:
+	// some callers only have an opaque acceptability test rather than a
+	// parsed VersionConstraints, and leave this unset in that case.
+	Constraints VersionConstraints
+}
+
+func (err ErrNoSuitableVersion) Error() string {
+	if len(err.Constraints) == 0 {
+		return fmt.Sprintf("no available releases of provider %s match the given version constraints", err.Provider)
+	}
+	return fmt.Sprintf(
+		"no available releases of provider %s match the given constraints %s",
+		err.Provider,
+		FormatVersionConstraints(err.Constraints),
+	)
+}
+
 // ErrPlatformNotSupported is an error type used to indicate that a particular
 // version of a provider isn't available for a particular target platform.
 //
@@ -108,15 +139,55 @@ type ErrPlatformNotSupported struct {
 	Provider addrs.Provider
 	Version  Version
 	Platform Platform
+
+	// Available, if non-empty, lists the platforms that the requested
+	// version does support, so that a caller can give the user an
+	// actionable hint instead of a bare "not available" error.
+	Available []Platform
+
+	// NewestSupportedVersion is the newest version of the provider known to
+	// support the requested platform. It is meaningful only when
+	// HaveNewestSupportedVersion is true.
+	NewestSupportedVersion     Version
+	HaveNewestSupportedVersion bool
 }
 
 func (err ErrPlatformNotSupported) Error() string {
-	return fmt.Sprintf(
-		"provider %s %s is not available for %s",
-		err.Provider,
-		err.Version,
-		err.Platform,
-	)
+	switch {
+	case len(err.Available) > 0 && err.HaveNewestSupportedVersion:
+		return fmt.Sprintf(
+			"provider %s %s is not available for %s; it supports %s, and the newest version supporting %s is %s",
+			err.Provider,
+			err.Version,
+			err.Platform,
+			platformsForDisplay(err.Available),
+			err.Platform,
+			err.NewestSupportedVersion,
+		)
+	case len(err.Available) > 0:
+		return fmt.Sprintf(
+			"provider %s %s is not available for %s; it supports %s",
+			err.Provider,
+			err.Version,
+			err.Platform,
+			platformsForDisplay(err.Available),
+		)
+	default:
+		return fmt.Sprintf(
+			"provider %s %s is not available for %s",
+			err.Provider,
+			err.Version,
+			err.Platform,
+		)
+	}
+}
+
+func platformsForDisplay(platforms []Platform) string {
+	strs := make([]string, len(platforms))
+	for i, p := range platforms {
+		strs[i] = p.String()
+	}
+	return strings.Join(strs, ", ")
 }
 
 // ErrQueryFailed is an error type used to indicate that the hostname given
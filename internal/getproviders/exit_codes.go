@@ -0,0 +1,87 @@
+package getproviders
+
+import (
+	"strings"
+)
+
+// Exit code values distinguishing why a provider installation failed, for
+// an install command to return from its Run method so that a CI pipeline
+// can branch on exit code alone -- retry a transient network error, page
+// on-call for an auth or verification failure, or flag a configuration
+// problem for a constraint conflict -- without parsing any error text.
+const (
+	ExitSuccess = 0
+
+	// ExitGenericFailure is for any failure that doesn't fall into one of
+	// the more specific classes below, such as a malformed command-line
+	// argument.
+	ExitGenericFailure = 1
+
+	// ExitNetworkFailure indicates that a registry or mirror host could not
+	// be reached, or didn't speak the expected provider registry protocol.
+	ExitNetworkFailure = 10
+
+	// ExitAuthFailure indicates that a host rejected, or required but
+	// wasn't given, authentication credentials.
+	ExitAuthFailure = 11
+
+	// ExitVerificationFailure indicates that a downloaded package failed a
+	// checksum or signature check.
+	ExitVerificationFailure = 12
+
+	// ExitConstraintConflict indicates that no available version of a
+	// provider satisfied the version constraints and other acceptability
+	// checks given for it.
+	ExitConstraintConflict = 13
+)
+
+// ExitCodeForInstallError classifies err, such as one returned by
+// ResolvePackages, ResolveBatch, or a PackageAuthentication check, into one
+// of the Exit* codes above, falling back to ExitGenericFailure for any
+// error it doesn't recognize.
+//
+// It shares its classification of the error types defined in errors.go
+// with NewInstallOutcome's FailureClass, so that a command's exit code and
+// its reported install telemetry always agree about what kind of failure
+// occurred.
+func ExitCodeForInstallError(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+
+	switch classifyInstallFailure(err) {
+	case "host_unreachable", "host_no_providers":
+		return ExitNetworkFailure
+	case "unauthorized":
+		return ExitAuthFailure
+	case "provider_not_known", "no_suitable_version":
+		return ExitConstraintConflict
+	}
+
+	if isVerificationFailure(err) {
+		return ExitVerificationFailure
+	}
+
+	return ExitGenericFailure
+}
+
+// isVerificationFailure recognizes the checksum and signature checks in
+// package_authentication.go by their error message, since -- unlike the
+// registry-query failures in errors.go -- they report failure as plain
+// fmt.Errorf values with no dedicated error type to switch on.
+func isVerificationFailure(err error) bool {
+	msg := err.Error()
+	for _, substr := range []string{
+		"checksum mismatch",
+		"signature verification failed",
+		"signature did not match any trusted key",
+		"checksums document does not include an entry",
+		"signing key has expired",
+		"signing key expired",
+	} {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package getproviders
+
+import (
+	"errors"
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestExitCodeForInstallError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitSuccess},
+		{"host unreachable", ErrHostUnreachable{Hostname: svchost.Hostname("example.com"), Wrapped: errors.New("no route to host")}, ExitNetworkFailure},
+		{"host no providers", ErrHostNoProviders{Hostname: svchost.Hostname("example.com")}, ExitNetworkFailure},
+		{"unauthorized", ErrUnauthorized{Hostname: svchost.Hostname("example.com")}, ExitAuthFailure},
+		{"provider not known", ErrProviderNotKnown{}, ExitConstraintConflict},
+		{"checksum mismatch", errors.New("checksum mismatch: expected aaaa, got bbbb"), ExitVerificationFailure},
+		{"signature verification failed", errors.New("signature verification failed: no valid signature"), ExitVerificationFailure},
+		{"unrecognized", errors.New("something else went wrong"), ExitGenericFailure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ExitCodeForInstallError(test.err)
+			if got != test.want {
+				t.Errorf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
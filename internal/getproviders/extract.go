@@ -0,0 +1,175 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// packageFilenamePattern matches the standard
+// terraform-provider-<type>_<version>_<os>_<arch>.zip naming convention
+// used for provider release artifacts.
+var packageFilenamePattern = regexp.MustCompile(`^terraform-provider-([a-zA-Z0-9_-]+)_([^_]+)_([a-zA-Z0-9]+)_([a-zA-Z0-9]+)\.zip$`)
+
+// ExtractedPackage is the result of ExtractPackageMeta: everything that can
+// be inferred about a provider distribution package just by examining it,
+// without consulting any external source of truth.
+type ExtractedPackage struct {
+	Provider addrs.Provider
+	Version  Version
+	Platform Platform
+
+	// SHA256Sum is populated when path refers to a zip archive.
+	SHA256Sum [sha256.Size]byte
+
+	// Hash is the HashSchemeDir content hash, populated when path refers
+	// to an already-unpacked directory rather than a zip archive, since in
+	// that case SHA256Sum cannot be computed without repacking it.
+	Hash string
+}
+
+// ExtractPackageMeta inspects the provider distribution package at path,
+// which may be either a zip archive or an already-unpacked directory named
+// according to the standard terraform-provider-<type>_<version>_<os>_<arch>
+// convention, and returns everything that can be inferred from it: its
+// address, version, target platform, and a content hash.
+//
+// This is the inverse of installation, and is intended for use by mirror
+// tooling, registry implementations, and provider publishing pipelines that
+// need to derive this metadata from a built artifact rather than being told
+// it up front. Since the provider's registry namespace cannot be inferred
+// from the filename alone, the returned address always uses
+// defaultNamespace.
+func ExtractPackageMeta(path, defaultNamespace string) (ExtractedPackage, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ExtractedPackage{}, err
+	}
+
+	base := filepath.Base(path)
+	if info.IsDir() {
+		// Unpacked directories are conventionally named the same as their
+		// originating zip file, minus the extension.
+		base = base + ".zip"
+	}
+	m := packageFilenamePattern.FindStringSubmatch(base)
+	if m == nil {
+		return ExtractedPackage{}, fmt.Errorf("%s does not match the terraform-provider-<type>_<version>_<os>_<arch> naming convention", filepath.Base(path))
+	}
+
+	version, err := ParseVersion(m[2])
+	if err != nil {
+		return ExtractedPackage{}, fmt.Errorf("invalid version %q in filename: %s", m[2], err)
+	}
+
+	ret := ExtractedPackage{
+		Provider: addrs.NewProvider(DefaultRegistryHost, defaultNamespace, m[1]),
+		Version:  version,
+		Platform: Platform{OS: m[3], Arch: m[4]},
+	}
+
+	if info.IsDir() {
+		hash, err := PackageDirHash(path)
+		if err != nil {
+			return ExtractedPackage{}, fmt.Errorf("failed to hash %s: %s", path, err)
+		}
+		ret.Hash = hash
+		return ret, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ExtractedPackage{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ExtractedPackage{}, fmt.Errorf("failed to hash %s: %s", path, err)
+	}
+	copy(ret.SHA256Sum[:], h.Sum(nil))
+
+	return ret, nil
+}
+
+// ExtractPackageArchive unpacks the zip archive at zipPath into destDir,
+// creating destDir if it doesn't already exist.
+//
+// Every extracted file's permissions are normalized rather than trusting
+// whatever was stored in the zip: a file the archive marked executable is
+// extracted 0755, and every other file 0644, regardless of what other bits
+// its zip header claimed. In particular this means no extracted file is
+// ever world-writable. This matters because destDir is typically a
+// provider's entry in a shared installation cache that every root module
+// requiring it links against, so a sloppy or tampered zip's permissions
+// would otherwise propagate to all of them.
+func ExtractPackageArchive(zipPath, destDir string) error {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %s", zipPath, err)
+	}
+	defer r.Close()
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+	destDir = filepath.Clean(destDir)
+
+	for _, f := range r.File {
+		destPath := filepath.Join(destDir, filepath.FromSlash(f.Name))
+		if destPath != destDir && !strings.HasPrefix(destPath, destDir+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid entry path %q in %s", f.Name, zipPath)
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(destPath, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return err
+		}
+		if err := extractArchiveFile(f, destPath); err != nil {
+			return fmt.Errorf("failed to extract %s from %s: %s", f.Name, zipPath, err)
+		}
+	}
+
+	return nil
+}
+
+func extractArchiveFile(f *zip.File, destPath string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(destPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, normalizedFileMode(f.Mode()))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, rc)
+	return err
+}
+
+// normalizedFileMode collapses an archive entry's stored mode down to
+// either 0755 or 0644, preserving only whether the entry's owner could
+// execute it and discarding every other permission bit the zip claimed,
+// including any group- or world-writable bits.
+func normalizedFileMode(stored os.FileMode) os.FileMode {
+	if stored&0100 != 0 {
+		return 0755
+	}
+	return 0644
+}
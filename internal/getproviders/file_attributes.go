@@ -0,0 +1,28 @@
+package getproviders
+
+// FileAttributeSettings configures how a copied provider package file
+// should have its extended attributes or ACLs handled once the copy
+// completes, for hardened environments -- SELinux-enforcing Linux hosts,
+// Windows systems with NTFS ACL-based execution policy -- that deny
+// execution of a binary whose attributes don't meet their profile.
+//
+// Every field is optional, and a zero-value FileAttributeSettings applies
+// no attributes at all, leaving the copy's attributes exactly as the
+// filesystem assigned them by default.
+type FileAttributeSettings struct {
+	// SELinuxContext, if non-empty, is applied to each copied file on
+	// Linux, equivalent to running chcon on it.
+	SELinuxContext string
+
+	// PreserveACLs requests that Windows NTFS ACLs be copied from the
+	// source file to the destination file.
+	PreserveACLs bool
+}
+
+// applyFileAttributes applies settings to destPath, which was just created
+// by copying srcPath, using whatever mechanism the current platform
+// supports. Platforms with no applicable mechanism ignore settings
+// entirely.
+func applyFileAttributes(srcPath, destPath string, settings FileAttributeSettings) error {
+	return applyPlatformFileAttributes(srcPath, destPath, settings)
+}
@@ -0,0 +1,22 @@
+// +build linux
+
+package getproviders
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// applyPlatformFileAttributes applies settings.SELinuxContext to destPath
+// via the equivalent of "chcon <context> <path>", when one was configured.
+// PreserveACLs has no meaning on Linux and is ignored.
+func applyPlatformFileAttributes(srcPath, destPath string, settings FileAttributeSettings) error {
+	if settings.SELinuxContext == "" {
+		return nil
+	}
+
+	if out, err := exec.Command("chcon", settings.SELinuxContext, destPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("chcon %s %s: %s: %s", settings.SELinuxContext, destPath, err, string(out))
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+// +build !linux,!windows
+
+package getproviders
+
+// applyPlatformFileAttributes is a no-op on platforms with no applicable
+// extended-attribute or ACL mechanism of their own.
+func applyPlatformFileAttributes(srcPath, destPath string, settings FileAttributeSettings) error {
+	return nil
+}
@@ -0,0 +1,36 @@
+// +build windows
+
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+)
+
+// applyPlatformFileAttributes copies NTFS ACLs from srcPath to destPath
+// when settings.PreserveACLs is set, using icacls to save srcPath's ACL to
+// a temporary description file and restore it onto destPath, since icacls
+// has no single-step "copy the ACL from this file to that one" operation.
+// SELinuxContext has no meaning on Windows and is ignored.
+func applyPlatformFileAttributes(srcPath, destPath string, settings FileAttributeSettings) error {
+	if !settings.PreserveACLs {
+		return nil
+	}
+
+	aclFile, err := ioutil.TempFile("", "terraform-provider-acl-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary ACL file: %s", err)
+	}
+	aclFile.Close()
+	defer os.Remove(aclFile.Name())
+
+	if out, err := exec.Command("icacls", srcPath, "/save", aclFile.Name(), "/q").CombinedOutput(); err != nil {
+		return fmt.Errorf("icacls save %s: %s: %s", srcPath, err, string(out))
+	}
+	if out, err := exec.Command("icacls", destPath, "/restore", aclFile.Name(), "/q").CombinedOutput(); err != nil {
+		return fmt.Errorf("icacls restore %s: %s: %s", destPath, err, string(out))
+	}
+	return nil
+}
@@ -1,6 +1,17 @@
 package getproviders
 
 import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sync"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
 	"github.com/hashicorp/terraform/addrs"
 )
 
@@ -8,30 +19,339 @@ import (
 // from a directory prefix in the local filesystem.
 type FilesystemMirrorSource struct {
 	baseDir string
+
+	mu    sync.Mutex
+	cache map[addrs.Provider]VersionList
 }
 
 var _ Source = (*FilesystemMirrorSource)(nil)
+var _ EnumerableSource = (*FilesystemMirrorSource)(nil)
 
 // NewFilesystemMirrorSource constructs and returns a new filesystem-based
 // mirror source with the given base directory.
 func NewFilesystemMirrorSource(baseDir string) *FilesystemMirrorSource {
 	return &FilesystemMirrorSource{
-		baseDir: baseDir,
+		baseDir: toLongPath(baseDir),
 	}
 }
 
-// AvailableVersions scans the directory structure under the source's base
-// directory for locally-mirrored packages for the given provider, returning
-// a list of version numbers for the providers it found.
+// AvailableVersions returns the locally-mirrored versions available for the
+// given provider, answered from an in-memory cache of a single scan of the
+// whole mirror directory.
+//
+// The first call to either AvailableVersions or AllAvailablePackages on a
+// given FilesystemMirrorSource populates that cache by walking the whole
+// mirror once; every call to either after that, including for other
+// providers, is answered from memory without touching the filesystem again.
+// PackageMeta does not use this cache and always reads the mirror
+// directory directly. Use Invalidate or Refresh if the mirror's contents
+// can change during the source's lifetime, such as when a MirrorSyncDaemon
+// is writing into the same directory concurrently.
 func (s *FilesystemMirrorSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
-	// TODO: Implement
-	panic("FilesystemMirrorSource.AvailableVersions not yet implemented")
+	cache, err := s.ensureCache()
+	if err != nil {
+		return nil, err
+	}
+	return cache[provider], nil
+}
+
+// AllAvailablePackages implements EnumerableSource by returning the same
+// whole-mirror scan that AvailableVersions already populates its cache
+// from, letting a caller enumerate every provider version the mirror has
+// without resolving each one individually.
+func (s *FilesystemMirrorSource) AllAvailablePackages() (map[addrs.Provider]VersionList, error) {
+	return s.ensureCache()
+}
+
+// Invalidate discards the source's cached scan of the mirror directory, so
+// that the next call to AvailableVersions or AllAvailablePackages triggers
+// a fresh one lazily.
+func (s *FilesystemMirrorSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = nil
+}
+
+// Refresh immediately re-scans the mirror directory and replaces the
+// cache, returning any error encountered, rather than waiting for the next
+// query to discover a stale cache lazily the way Invalidate does.
+func (s *FilesystemMirrorSource) Refresh() error {
+	cache, err := AllAvailablePackages(s.baseDir)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = cache
+	return nil
+}
+
+func (s *FilesystemMirrorSource) ensureCache() (map[addrs.Provider]VersionList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cache != nil {
+		return s.cache, nil
+	}
+
+	cache, err := AllAvailablePackages(s.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	s.cache = cache
+	return s.cache, nil
 }
 
 // PackageMeta checks to see if the source's base directory contains a
 // local copy of the distribution package for the given provider version on
 // the given target, and returns the metadata about it if so.
+//
+// An optional per-version "<version>.json" index document, in the same
+// shape the HTTP provider mirror protocol serves, is preferred over both
+// when present. Otherwise, both layouts recognized by AvailableVersions
+// are supported here too: the unpacked layout
+// (baseDir/.../<version>/<os_arch>, as a PackageLocalDir) and the packed
+// layout (a terraform-provider-<type>_<version>_<os>_<arch>.zip file
+// directly in the type directory, as a PackageLocalArchive). The unpacked
+// layout is preferred when both are present, since it doesn't require
+// re-hashing a zip file on every call.
 func (s *FilesystemMirrorSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
-	// TODO: Implement
-	panic("FilesystemMirrorSource.PackageMeta not yet implemented")
+	typeDir, ok := resolveMirrorTypeDir(s.baseDir, provider)
+	if !ok {
+		return PackageMeta{}, ErrPlatformNotSupported{
+			Provider: provider,
+			Version:  version,
+			Platform: target,
+		}
+	}
+
+	if meta, ok, err := packageMetaFromMirrorIndex(typeDir, version, target); err != nil {
+		return PackageMeta{}, err
+	} else if ok {
+		return meta, nil
+	}
+
+	if meta, ok, err := unpackedPackageMeta(typeDir, version, target); err != nil {
+		return PackageMeta{}, err
+	} else if ok {
+		return meta, nil
+	}
+
+	if meta, ok, err := packedPackageMeta(typeDir, version, target); err != nil {
+		return PackageMeta{}, err
+	} else if ok {
+		return meta, nil
+	}
+
+	return PackageMeta{}, ErrPlatformNotSupported{
+		Provider: provider,
+		Version:  version,
+		Platform: target,
+	}
+}
+
+func unpackedPackageMeta(typeDir string, version Version, target Platform) (PackageMeta, bool, error) {
+	dir := filepath.Join(typeDir, version.String(), target.String())
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return PackageMeta{}, false, nil
+	}
+
+	// The original zip bytes aren't preserved for an unpacked package, so
+	// SHA256Sum can't be computed; an HashSchemeDir ("h1:") hash of the
+	// unpacked contents is recorded in Hashes instead, so this package can
+	// still be verified against a lock file that recorded one.
+	hash, err := PackageDirHash(dir)
+	if err != nil {
+		return PackageMeta{}, false, fmt.Errorf("failed to hash %s: %s", dir, err)
+	}
+
+	protocolVersions, err := protocolVersionsFromUnpackedDir(dir)
+	if err != nil {
+		return PackageMeta{}, false, err
+	}
+
+	return PackageMeta{
+		ProtocolVersions: protocolVersions,
+		TargetPlatform:   target,
+		Filename:         packedFilename(filepath.Base(typeDir), version, target),
+		Location:         PackageLocalDir(dir),
+		Hashes:           []string{hash},
+
+		// SizeBytes is left unset: a directory's own size on disk isn't
+		// meaningfully comparable to another package's packed size, the
+		// same reasoning PackageSize uses. ModTime is still worth
+		// recording, since the directory's mtime changes whenever an entry
+		// is added or removed from it.
+		ModTime: info.ModTime(),
+	}, true, nil
+}
+
+func packedPackageMeta(typeDir string, version Version, target Platform) (PackageMeta, bool, error) {
+	filename := packedFilename(filepath.Base(typeDir), version, target)
+	path := filepath.Join(typeDir, filename)
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return PackageMeta{}, false, nil
+	}
+
+	protocolVersions, err := protocolVersionsFromZip(path)
+	if err != nil {
+		return PackageMeta{}, false, err
+	}
+
+	sum, ok, err := sidecarSHA256(typeDir, filename)
+	if err != nil {
+		return PackageMeta{}, false, err
+	}
+	if !ok {
+		// No sidecar checksum was recorded ahead of time, so the whole
+		// package has to be read and hashed directly.
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return PackageMeta{}, false, fmt.Errorf("failed to read %s: %s", path, err)
+		}
+		sum = sha256.Sum256(data)
+	}
+
+	return PackageMeta{
+		ProtocolVersions: protocolVersions,
+		TargetPlatform:   target,
+		Filename:         filename,
+		Location:         PackageLocalArchive(path),
+		SHA256Sum:        sum,
+		SizeBytes:        info.Size(),
+		ModTime:          info.ModTime(),
+	}, true, nil
+}
+
+// packedFilename renders the standard
+// terraform-provider-<type>_<version>_<os>_<arch>.zip filename that both
+// the packed-layout archive and the directory an unpacked package was
+// extracted from are conventionally named after.
+func packedFilename(providerType string, version Version, target Platform) string {
+	return fmt.Sprintf("terraform-provider-%s_%s_%s.zip", providerType, version, target)
+}
+
+// AllAvailablePackages scans the given mirror directory -- which is
+// expected to follow the standard baseDir/hostname/namespace/type/
+// version/os_arch layout -- and returns every provider version it finds
+// available there, without needing a caller to already know which
+// providers to ask about.
+//
+// This is intended for mirror operators, such as the mirror sync tooling
+// and "what changed since yesterday" reports built on DiffVersionLists, who
+// need to see the whole mirror's contents rather than resolve a specific
+// set of requirements against it.
+//
+// The scan runs under a "getproviders.scan_phase" pprof label so that a CPU
+// profile taken while scanning a large mirror attributes the time spent
+// here to this phase specifically, distinct from whatever the caller does
+// with the result.
+func AllAvailablePackages(baseDir string) (map[addrs.Provider]VersionList, error) {
+	baseDir = toLongPath(baseDir)
+	var ret map[addrs.Provider]VersionList
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("getproviders.scan_phase", "scan_mirror"), func(context.Context) {
+		ret, err = scanMirrorPackages(baseDir)
+	})
+	return ret, err
+}
+
+func scanMirrorPackages(baseDir string) (map[addrs.Provider]VersionList, error) {
+	ret := make(map[addrs.Provider]VersionList)
+
+	hostEntries, err := readDirIfExists(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", baseDir, err)
+	}
+	for _, hostEntry := range hostEntries {
+		if !hostEntry.IsDir() {
+			continue
+		}
+		hostname, err := svchost.ForComparison(hostEntry.Name())
+		if err != nil {
+			continue // not a valid hostname directory
+		}
+		hostDir := filepath.Join(baseDir, hostEntry.Name())
+
+		namespaceEntries, err := readDirIfExists(hostDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", hostDir, err)
+		}
+		for _, namespaceEntry := range namespaceEntries {
+			if !namespaceEntry.IsDir() {
+				continue
+			}
+			namespaceDir := filepath.Join(hostDir, namespaceEntry.Name())
+
+			typeEntries, err := readDirIfExists(namespaceDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", namespaceDir, err)
+			}
+			for _, typeEntry := range typeEntries {
+				if !typeEntry.IsDir() {
+					continue
+				}
+				provider := addrs.NewProvider(hostname, namespaceEntry.Name(), typeEntry.Name())
+				typeDir := filepath.Join(namespaceDir, typeEntry.Name())
+
+				versions, err := scanMirrorVersions(typeDir)
+				if err != nil {
+					return nil, err
+				}
+				addMirrorScanResult(ret, provider, versions)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// scanMirrorVersions returns the de-duplicated set of versions found
+// directly in typeDir. If typeDir has an index.json document it's read
+// from that alone, without walking the directory at all; otherwise
+// scanMirrorVersions walks typeDir, recognizing both the unpacked layout
+// (a version-numbered subdirectory per package) and the packed layout (a
+// terraform-provider-<type>_<version>_<os>_<arch>.zip file directly in
+// typeDir), with a version present in both only reported once.
+func scanMirrorVersions(typeDir string) (VersionList, error) {
+	if versions, ok, err := readMirrorIndex(typeDir); err != nil {
+		return nil, err
+	} else if ok {
+		return versions, nil
+	}
+
+	entries, err := readDirIfExists(typeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+
+	seen := make(map[string]bool)
+	var versions VersionList
+	addIfNew := func(version Version) {
+		key := version.String()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		versions = append(versions, version)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if version, err := ParseVersion(entry.Name()); err == nil {
+				addIfNew(version)
+			}
+			continue
+		}
+
+		if m := packageFilenamePattern.FindStringSubmatch(entry.Name()); m != nil {
+			if version, err := ParseVersion(m[2]); err == nil {
+				addIfNew(version)
+			}
+		}
+	}
+
+	return versions, nil
 }
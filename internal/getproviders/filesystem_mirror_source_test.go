@@ -0,0 +1,74 @@
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAllAvailablePackages(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 2, 2, 3)
+	defer close()
+
+	got, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 4 { // 2 namespaces * 2 types
+		t.Errorf("wrong number of providers: got %d, want 4", len(got))
+	}
+	for provider, versions := range got {
+		if len(versions) != 3 {
+			t.Errorf("wrong number of versions for %s: got %d, want 3", provider, len(versions))
+		}
+	}
+}
+
+func BenchmarkAllAvailablePackages(b *testing.B) {
+	baseDir, close := testSyntheticMirror(b, 20, 20, 10)
+	defer close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := AllAvailablePackages(baseDir); err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+// testSyntheticMirror creates a temporary directory laid out like a
+// filesystem mirror, with namespaceCount*typeCount providers under
+// registry.terraform.io, each with versionCount available versions, for
+// use in tests and benchmarks that need a mirror of a known size without
+// checking in a large fixture tree.
+func testSyntheticMirror(t testing.TB, namespaceCount, typeCount, versionCount int) (string, func()) {
+	t.Helper()
+
+	baseDir, err := ioutil.TempDir("", "terraform-getproviders-mirror")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+
+	for ni := 0; ni < namespaceCount; ni++ {
+		for ti := 0; ti < typeCount; ti++ {
+			for vi := 0; vi < versionCount; vi++ {
+				dir := filepath.Join(
+					baseDir, "registry.terraform.io",
+					fmt.Sprintf("namespace%d", ni),
+					fmt.Sprintf("type%d", ti),
+					fmt.Sprintf("%d.0.0", vi),
+					"linux_amd64",
+				)
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatalf("failed to create %s: %s", dir, err)
+				}
+			}
+		}
+	}
+
+	return baseDir, func() {
+		os.RemoveAll(baseDir)
+	}
+}
@@ -0,0 +1,250 @@
+package getproviders
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/apparentlymart/go-versions/versions"
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// fixtureExchange is one recorded Source method call and its result, as
+// captured by RecordingSource and replayed by FixtureSource.
+type fixtureExchange struct {
+	Call     string `json:"call"` // "AvailableVersions" or "PackageMeta"
+	Provider string `json:"provider"`
+	Version  string `json:"version,omitempty"`
+	Platform string `json:"platform,omitempty"`
+
+	AvailableVersions []string            `json:"availableVersions,omitempty"`
+	PackageMeta       *fixturePackageMeta `json:"packageMeta,omitempty"`
+	Error             string              `json:"error,omitempty"`
+}
+
+type fixturePackageMeta struct {
+	ProtocolVersions []string `json:"protocolVersions,omitempty"`
+	TargetPlatform   string   `json:"targetPlatform"`
+	Filename         string   `json:"filename"`
+	LocationURL      string   `json:"locationURL"`
+	SHA256Sum        string   `json:"sha256Sum"`
+}
+
+// RecordingSource wraps another Source and, while passing its results
+// through unmodified, appends a fixture describing each call and result to
+// an in-memory log that Save can write out for FixtureSource to replay
+// later. This is how a resolution bug a user can reproduce against the real
+// registry, but a developer can't, gets turned into a deterministic,
+// offline, shareable test case.
+type RecordingSource struct {
+	Wrapped Source
+
+	mu        sync.Mutex
+	path      string
+	exchanges []fixtureExchange
+}
+
+// NewRecordingSource constructs a RecordingSource that delegates to wrapped
+// and will write its recorded fixtures to path when Save is called.
+func NewRecordingSource(wrapped Source, path string) *RecordingSource {
+	return &RecordingSource{Wrapped: wrapped, path: path}
+}
+
+var _ Source = (*RecordingSource)(nil)
+
+// AvailableVersions delegates to Wrapped and records the call and its
+// result.
+func (s *RecordingSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	result, err := s.Wrapped.AvailableVersions(provider)
+
+	exchange := fixtureExchange{
+		Call:     "AvailableVersions",
+		Provider: provider.String(),
+	}
+	if err != nil {
+		exchange.Error = err.Error()
+	} else {
+		strs := make([]string, len(result))
+		for i, v := range result {
+			strs[i] = v.String()
+		}
+		exchange.AvailableVersions = strs
+	}
+	s.append(exchange)
+
+	return result, err
+}
+
+// PackageMeta delegates to Wrapped and records the call and its result.
+func (s *RecordingSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	result, err := s.Wrapped.PackageMeta(provider, version, target)
+
+	exchange := fixtureExchange{
+		Call:     "PackageMeta",
+		Provider: provider.String(),
+		Version:  version.String(),
+		Platform: target.String(),
+	}
+	if err != nil {
+		exchange.Error = err.Error()
+	} else {
+		exchange.PackageMeta = newFixturePackageMeta(result)
+	}
+	s.append(exchange)
+
+	return result, err
+}
+
+func (s *RecordingSource) append(exchange fixtureExchange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exchanges = append(s.exchanges, exchange)
+}
+
+// Save writes the fixtures recorded so far to the configured path, as
+// indented JSON that LoadFixtureSource can read back.
+func (s *RecordingSource) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(struct {
+		Exchanges []fixtureExchange `json:"exchanges"`
+	}{s.exchanges}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture recording: %s", err)
+	}
+	if err := ioutil.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write fixture recording to %s: %s", s.path, err)
+	}
+	return nil
+}
+
+func newFixturePackageMeta(meta PackageMeta) *fixturePackageMeta {
+	protoStrs := make([]string, len(meta.ProtocolVersions))
+	for i, v := range meta.ProtocolVersions {
+		protoStrs[i] = v.String()
+	}
+
+	var locationURL string
+	if httpURL, ok := meta.Location.(PackageHTTPURL); ok {
+		locationURL = string(httpURL)
+	}
+
+	return &fixturePackageMeta{
+		ProtocolVersions: protoStrs,
+		TargetPlatform:   meta.TargetPlatform.String(),
+		Filename:         meta.Filename,
+		LocationURL:      locationURL,
+		SHA256Sum:        hex.EncodeToString(meta.SHA256Sum[:]),
+	}
+}
+
+// FixtureSource is a Source that deterministically replays the exchanges
+// previously captured by a RecordingSource, for offline reproduction of a
+// user-reported resolution bug or for hermetic integration tests that
+// shouldn't depend on a live registry.
+type FixtureSource struct {
+	byAvailableVersions map[string]fixtureExchange
+	byPackageMeta       map[string]fixtureExchange
+}
+
+// LoadFixtureSource reads a fixture file previously written by
+// RecordingSource.Save.
+func LoadFixtureSource(path string) (*FixtureSource, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture file %s: %s", path, err)
+	}
+
+	var raw struct {
+		Exchanges []fixtureExchange `json:"exchanges"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid fixture file %s: %s", path, err)
+	}
+
+	source := &FixtureSource{
+		byAvailableVersions: make(map[string]fixtureExchange),
+		byPackageMeta:       make(map[string]fixtureExchange),
+	}
+	for _, exchange := range raw.Exchanges {
+		switch exchange.Call {
+		case "AvailableVersions":
+			source.byAvailableVersions[exchange.Provider] = exchange
+		case "PackageMeta":
+			key := exchange.Provider + "@" + exchange.Version + "@" + exchange.Platform
+			source.byPackageMeta[key] = exchange
+		default:
+			return nil, fmt.Errorf("invalid fixture file %s: unrecognized call %q", path, exchange.Call)
+		}
+	}
+	return source, nil
+}
+
+var _ Source = (*FixtureSource)(nil)
+
+// AvailableVersions replays the AvailableVersions fixture recorded for
+// provider, or fails if none was recorded.
+func (s *FixtureSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	exchange, ok := s.byAvailableVersions[provider.String()]
+	if !ok {
+		return nil, fmt.Errorf("no recorded AvailableVersions fixture for %s", provider)
+	}
+	if exchange.Error != "" {
+		return nil, errors.New(exchange.Error)
+	}
+
+	ret := make(VersionList, len(exchange.AvailableVersions))
+	for i, str := range exchange.AvailableVersions {
+		v, err := ParseVersion(str)
+		if err != nil {
+			return nil, fmt.Errorf("fixture contains invalid version %q: %s", str, err)
+		}
+		ret[i] = v
+	}
+	return ret, nil
+}
+
+// PackageMeta replays the PackageMeta fixture recorded for provider at
+// version targeting target, or fails if none was recorded.
+func (s *FixtureSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	key := provider.String() + "@" + version.String() + "@" + target.String()
+	exchange, ok := s.byPackageMeta[key]
+	if !ok {
+		return PackageMeta{}, fmt.Errorf("no recorded PackageMeta fixture for %s %s on %s", provider, version, target)
+	}
+	if exchange.Error != "" {
+		return PackageMeta{}, errors.New(exchange.Error)
+	}
+
+	fixture := exchange.PackageMeta
+	protoVersions := make(VersionList, len(fixture.ProtocolVersions))
+	for i, str := range fixture.ProtocolVersions {
+		v, err := versions.ParseVersion(str)
+		if err != nil {
+			return PackageMeta{}, fmt.Errorf("fixture contains invalid protocol version %q: %s", str, err)
+		}
+		protoVersions[i] = v
+	}
+
+	platform, err := ParsePlatform(fixture.TargetPlatform)
+	if err != nil {
+		return PackageMeta{}, fmt.Errorf("fixture contains invalid target platform %q: %s", fixture.TargetPlatform, err)
+	}
+
+	var sha256Sum [32]byte
+	if _, err := hex.Decode(sha256Sum[:], []byte(fixture.SHA256Sum)); err != nil {
+		return PackageMeta{}, fmt.Errorf("fixture contains invalid SHA256 hash %q: %s", fixture.SHA256Sum, err)
+	}
+
+	return PackageMeta{
+		ProtocolVersions: protoVersions,
+		TargetPlatform:   platform,
+		Filename:         fixture.Filename,
+		Location:         PackageHTTPURL(fixture.LocationURL),
+		SHA256Sum:        sha256Sum,
+	}, nil
+}
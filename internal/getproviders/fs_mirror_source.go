@@ -0,0 +1,142 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"path"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// FSMirrorSource is a Source that reads providers and their metadata from an
+// fs.FS, the read-only counterpart to FilesystemMirrorSource for callers
+// that have a virtual filesystem rather than a real directory on disk -- an
+// embed.FS baked into a custom Terraform build to ship a bundle of
+// providers with it, an fstest.MapFS in a unit test, or a zip archive
+// opened with zip.NewReader.
+//
+// It recognizes the same hostname/namespace/type layout as
+// FilesystemMirrorSource, using fs.FS's "/"-separated paths rather than
+// path/filepath, but only the packed (zip archive) layout: fs.FS has no
+// notion of a directory having been extracted from an archive in place, so
+// the unpacked layout FilesystemMirrorSource also supports doesn't apply
+// here. There's likewise no write-side counterpart to this source, since
+// fs.FS is read-only.
+type FSMirrorSource struct {
+	fsys fs.FS
+}
+
+var _ Source = (*FSMirrorSource)(nil)
+
+// NewFSMirrorSource constructs and returns a new FSMirrorSource reading
+// from fsys.
+func NewFSMirrorSource(fsys fs.FS) *FSMirrorSource {
+	return &FSMirrorSource{fsys: fsys}
+}
+
+// AvailableVersions returns the versions packed directly under provider's
+// type directory within fsys.
+func (s *FSMirrorSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	typeDir := fsMirrorTypeDir(provider)
+
+	entries, err := fs.ReadDir(s.fsys, typeDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+
+	seen := make(map[string]bool)
+	var result VersionList
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := packageFilenamePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		version, err := ParseVersion(m[2])
+		if err != nil || seen[version.String()] {
+			continue
+		}
+		seen[version.String()] = true
+		result = append(result, version)
+	}
+	result.Sort()
+	return result, nil
+}
+
+// PackageMeta returns the metadata for the packed archive for provider at
+// version and target, read from fsys.
+func (s *FSMirrorSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	typeDir := fsMirrorTypeDir(provider)
+	filename := packedFilename(provider.Type, version, target)
+	archivePath := path.Join(typeDir, filename)
+
+	data, err := fs.ReadFile(s.fsys, archivePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return PackageMeta{}, ErrPlatformNotSupported{
+				Provider: provider,
+				Version:  version,
+				Platform: target,
+			}
+		}
+		return PackageMeta{}, fmt.Errorf("failed to read %s: %s", archivePath, err)
+	}
+
+	protocolVersions, err := protocolVersionsFromZipData(data)
+	if err != nil {
+		return PackageMeta{}, fmt.Errorf("failed to read %s: %s", archivePath, err)
+	}
+
+	return PackageMeta{
+		ProtocolVersions: protocolVersions,
+		TargetPlatform:   target,
+		Filename:         filename,
+		Location:         PackageFSArchive{FS: s.fsys, Path: archivePath},
+		SHA256Sum:        sha256.Sum256(data),
+	}, nil
+}
+
+func fsMirrorTypeDir(provider addrs.Provider) string {
+	return path.Join(string(provider.Hostname), provider.Namespace, provider.Type)
+}
+
+// protocolVersionsFromZipData is the in-memory counterpart to
+// protocolVersionsFromZip, for callers like FSMirrorSource that have
+// already read the whole archive into memory rather than having it as a
+// file on local disk that zip.OpenReader can open directly.
+func protocolVersionsFromZipData(data []byte) (VersionList, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %s", err)
+	}
+
+	names := make([]string, len(r.File))
+	for i, f := range r.File {
+		names[i] = f.Name
+		if f.Name != pluginManifestFilename {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", pluginManifestFilename, err)
+		}
+		manifestData, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", pluginManifestFilename, err)
+		}
+		return parsePluginManifest(manifestData)
+	}
+
+	return protocolVersionsFromBinaryNames(names), nil
+}
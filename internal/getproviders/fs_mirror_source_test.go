@@ -0,0 +1,75 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestFSMirrorSource(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	fw, err := zw.Create(pluginManifestFilename)
+	if err != nil {
+		t.Fatalf("failed to create manifest in zip: %s", err)
+	}
+	if _, err := fw.Write([]byte(`{"protocolVersions": ["5.0"]}`)); err != nil {
+		t.Fatalf("failed to write manifest: %s", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %s", err)
+	}
+
+	fsys := fstest.MapFS{
+		"registry.terraform.io/hashicorp/null/terraform-provider-null_1.0.0_linux_amd64.zip": &fstest.MapFile{
+			Data: archive.Bytes(),
+		},
+	}
+
+	source := NewFSMirrorSource(fsys)
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+
+	versions, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(versions) != 1 || versions[0].String() != "1.0.0" {
+		t.Fatalf("wrong versions: got %v", versions)
+	}
+
+	meta, err := source.PackageMeta(provider, versions[0], Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(meta.ProtocolVersions) != 1 || meta.ProtocolVersions[0].String() != "5.0.0" {
+		t.Errorf("wrong protocol versions: got %v", meta.ProtocolVersions)
+	}
+	loc, ok := meta.Location.(PackageFSArchive)
+	if !ok {
+		t.Fatalf("wrong location type %T", meta.Location)
+	}
+	if loc.Path != "registry.terraform.io/hashicorp/null/terraform-provider-null_1.0.0_linux_amd64.zip" {
+		t.Errorf("wrong path: got %s", loc.Path)
+	}
+}
+
+func TestFSMirrorSourceNotFound(t *testing.T) {
+	source := NewFSMirrorSource(fstest.MapFS{})
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+
+	versions, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(versions) != 0 {
+		t.Errorf("expected no versions, got %v", versions)
+	}
+
+	_, err = source.PackageMeta(provider, versionMust(t, "1.0.0"), Platform{OS: "linux", Arch: "amd64"})
+	if _, ok := err.(ErrPlatformNotSupported); !ok {
+		t.Fatalf("wrong error type %T: %s", err, err)
+	}
+}
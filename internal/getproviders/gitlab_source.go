@@ -0,0 +1,168 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// GitLabPackageRegistrySource is a Source that reads provider packages
+// directly from a GitLab generic package registry, the common home for
+// internal providers published from a GitLab CI pipeline, using GitLab's
+// own packages API and PRIVATE-TOKEN authentication rather than requiring a
+// registry-protocol proxy in front of it.
+//
+// A provider's packages are expected to have been published under the
+// project's generic package registry using "terraform-provider-<type>" as
+// the package name and the provider version as the package version, with
+// each platform's zip archive attached as a file named the same as
+// packedFilename produces -- the layout WriteMirrorIndex and PublishRelease
+// already produce files compatible with.
+type GitLabPackageRegistrySource struct {
+	// BaseURL is the GitLab instance's base URL, e.g. "https://gitlab.com".
+	BaseURL string
+
+	// ProjectID is the numeric or URL-encoded "group/project" path ID of
+	// the GitLab project whose package registry providers are published
+	// to.
+	ProjectID string
+
+	// AuthToken is sent as the PRIVATE-TOKEN header on every request. GitLab
+	// accepts a personal access token, a project access token, or a CI job
+	// token (CI_JOB_TOKEN) interchangeably through that same header, so
+	// this source doesn't need to distinguish between them.
+	AuthToken string
+
+	// HTTPClient is used to make requests. A nil HTTPClient uses
+	// http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Source = (*GitLabPackageRegistrySource)(nil)
+
+type gitlabPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// AvailableVersions lists the versions GitLab's package registry has
+// published for provider, by querying the project's packages API filtered
+// to the conventional "terraform-provider-<type>" package name.
+func (s *GitLabPackageRegistrySource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	packageName := gitlabPackageName(provider)
+	endpoint := fmt.Sprintf(
+		"%s/api/v4/projects/%s/packages?package_name=%s&per_page=100",
+		strings.TrimRight(s.BaseURL, "/"),
+		url.PathEscape(s.ProjectID),
+		url.QueryEscape(packageName),
+	)
+
+	var packages []gitlabPackage
+	if err := s.getJSON(endpoint, &packages); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var versions VersionList
+	for _, pkg := range packages {
+		if pkg.Name != packageName || seen[pkg.Version] {
+			continue
+		}
+		version, err := ParseVersion(pkg.Version)
+		if err != nil {
+			continue
+		}
+		seen[pkg.Version] = true
+		versions = append(versions, version)
+	}
+	versions.Sort()
+	return versions, nil
+}
+
+// PackageMeta builds the metadata for a single provider package, pointing
+// at its GitLab generic package registry download URL.
+//
+// GitLab's generic package registry has no equivalent of a registry
+// protocol's separate checksum or signature documents, so the returned
+// PackageMeta carries no hash; pair this source with a
+// NewArchiveChecksumMatchAuthentication check driven from a
+// separately-published SHA256SUMS file if verification is required.
+func (s *GitLabPackageRegistrySource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	packageName := gitlabPackageName(provider)
+	filename := packedFilename(provider.Type, version, target)
+
+	downloadURL := fmt.Sprintf(
+		"%s/api/v4/projects/%s/packages/generic/%s/%s/%s",
+		strings.TrimRight(s.BaseURL, "/"),
+		url.PathEscape(s.ProjectID),
+		url.PathEscape(packageName),
+		url.PathEscape(version.String()),
+		url.PathEscape(filename),
+	)
+
+	return PackageMeta{
+		TargetPlatform: target,
+		Filename:       filename,
+		Location:       PackageHTTPURL(downloadURL),
+	}, nil
+}
+
+func (s *GitLabPackageRegistrySource) getJSON(endpoint string, out interface{}) error {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if s.AuthToken != "" {
+		req.Header.Set("PRIVATE-TOKEN", s.AuthToken)
+	}
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ErrHostUnreachable{Hostname: s.hostname(), Wrapped: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return ErrUnauthorized{Hostname: s.hostname(), HaveCredentials: s.AuthToken != ""}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("GitLab package registry at %s responded with status %s", endpoint, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response from GitLab package registry: %s", err)
+	}
+	return nil
+}
+
+// hostname extracts a comparable svchost.Hostname from BaseURL for use in
+// the ErrHostUnreachable and ErrUnauthorized errors getJSON returns, falling
+// back to the zero Hostname if BaseURL can't be parsed.
+func (s *GitLabPackageRegistrySource) hostname() svchost.Hostname {
+	parsed, err := url.Parse(s.BaseURL)
+	if err != nil {
+		return ""
+	}
+	hostname, err := svchost.ForComparison(parsed.Host)
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+// gitlabPackageName is the conventional GitLab generic package name a
+// provider's packages are expected to be published under.
+func gitlabPackageName(provider addrs.Provider) string {
+	return "terraform-provider-" + provider.Type
+}
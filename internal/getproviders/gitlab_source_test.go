@@ -0,0 +1,69 @@
+package getproviders
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestGitLabPackageRegistrySource(t *testing.T) {
+	var gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+		fmt.Fprint(w, `[
+			{"name": "terraform-provider-null", "version": "3.1.0"},
+			{"name": "terraform-provider-null", "version": "3.2.0"},
+			{"name": "terraform-provider-other", "version": "1.0.0"}
+		]`)
+	}))
+	defer server.Close()
+
+	source := &GitLabPackageRegistrySource{
+		BaseURL:   server.URL,
+		ProjectID: "123",
+		AuthToken: "glpat-example",
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "acme", "null")
+	versions, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(versions) != 2 || versions[0].String() != "3.1.0" || versions[1].String() != "3.2.0" {
+		t.Fatalf("wrong versions: got %v", versions)
+	}
+	if gotToken != "glpat-example" {
+		t.Errorf("wrong PRIVATE-TOKEN header: got %q", gotToken)
+	}
+
+	meta, err := source.PackageMeta(provider, versions[1], Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	url, ok := meta.Location.(PackageHTTPURL)
+	if !ok {
+		t.Fatalf("wrong location type %T", meta.Location)
+	}
+	wantURL := PackageHTTPURL(server.URL + "/api/v4/projects/123/packages/generic/terraform-provider-null/3.2.0/terraform-provider-null_3.2.0_linux_amd64.zip")
+	if url != wantURL {
+		t.Errorf("wrong download URL:\ngot:  %s\nwant: %s", url, wantURL)
+	}
+}
+
+func TestGitLabPackageRegistrySourceUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	source := &GitLabPackageRegistrySource{BaseURL: server.URL, ProjectID: "123"}
+	provider := addrs.NewProvider(DefaultRegistryHost, "acme", "null")
+
+	_, err := source.AvailableVersions(provider)
+	if _, ok := err.(ErrUnauthorized); !ok {
+		t.Fatalf("wrong error type %T: %s", err, err)
+	}
+}
@@ -0,0 +1,182 @@
+package getproviders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2/google"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// googleArtifactRegistryScope is the OAuth2 scope requested of Application
+// Default Credentials to call the Artifact Registry API.
+const googleArtifactRegistryScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// GoogleArtifactRegistrySource is a Source that reads provider packages
+// published to a Google Artifact Registry generic repository,
+// authenticating with Application Default Credentials -- a service account
+// key, workload identity federation, or the ambient credentials of the
+// environment it's running in -- rather than requiring a long-lived token
+// to be configured by hand.
+//
+// A provider's packages are expected to have been published as generic
+// Artifact Registry packages named "terraform-provider-<type>", with each
+// platform's zip archive uploaded as the file named the same as
+// packedFilename produces.
+type GoogleArtifactRegistrySource struct {
+	Project    string
+	Location   string
+	Repository string
+
+	// Client, when set, is used to make requests already authenticated
+	// however the caller prefers; this is mainly for tests. A nil Client
+	// is replaced on first use with one built from google.DefaultClient
+	// using googleArtifactRegistryScope.
+	Client *http.Client
+
+	// APIEndpoint and DownloadEndpoint override the Artifact Registry
+	// control API and generic-repository download base URLs respectively,
+	// which are otherwise the real Google endpoints. Both exist mainly so
+	// tests can point this source at an httptest server.
+	APIEndpoint      string
+	DownloadEndpoint string
+
+	mu sync.Mutex
+}
+
+var _ Source = (*GoogleArtifactRegistrySource)(nil)
+
+type artifactRegistryListVersionsResponse struct {
+	Versions []struct {
+		Name string `json:"name"`
+	} `json:"versions"`
+}
+
+// AvailableVersions lists the versions Artifact Registry has published for
+// provider, by listing the generic package's versions.
+func (s *GoogleArtifactRegistrySource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/v1/projects/%s/locations/%s/repositories/%s/packages/%s/versions",
+		s.apiEndpoint(), s.Project, s.Location, s.Repository,
+		url.PathEscape(artifactRegistryPackageName(provider)),
+	)
+
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return nil, ErrHostUnreachable{Hostname: s.hostname(), Wrapped: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, ErrUnauthorized{Hostname: s.hostname(), HaveCredentials: true}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Google Artifact Registry responded with status %s", resp.Status)
+	}
+
+	var listing artifactRegistryListVersionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("invalid response from Google Artifact Registry: %s", err)
+	}
+
+	seen := make(map[string]bool)
+	var result VersionList
+	for _, v := range listing.Versions {
+		// v.Name is the version's full resource name; the version string
+		// itself is the last path segment.
+		versionStr := v.Name
+		if idx := strings.LastIndex(versionStr, "/"); idx >= 0 {
+			versionStr = versionStr[idx+1:]
+		}
+		version, err := ParseVersion(versionStr)
+		if err != nil || seen[version.String()] {
+			continue
+		}
+		seen[version.String()] = true
+		result = append(result, version)
+	}
+	result.Sort()
+	return result, nil
+}
+
+// PackageMeta builds the metadata for a single provider package, pointing
+// at its Artifact Registry generic-repository download URL.
+//
+// Downloading that URL requires the same Application Default Credentials
+// bearer token this source uses to list versions, attached as an
+// "Authorization: Bearer <token>" header.
+func (s *GoogleArtifactRegistrySource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	filename := packedFilename(provider.Type, version, target)
+	downloadURL := fmt.Sprintf(
+		"%s/projects/%s/repositories/%s/%s/%s/%s",
+		s.downloadEndpoint(), s.Project, s.Repository,
+		artifactRegistryPackageName(provider), version.String(), filename,
+	)
+
+	return PackageMeta{
+		TargetPlatform: target,
+		Filename:       filename,
+		Location:       PackageHTTPURL(downloadURL),
+	}, nil
+}
+
+func (s *GoogleArtifactRegistrySource) apiEndpoint() string {
+	if s.APIEndpoint != "" {
+		return s.APIEndpoint
+	}
+	return "https://artifactregistry.googleapis.com"
+}
+
+func (s *GoogleArtifactRegistrySource) downloadEndpoint() string {
+	if s.DownloadEndpoint != "" {
+		return s.DownloadEndpoint
+	}
+	return fmt.Sprintf("https://%s-generic.pkg.dev", s.Location)
+}
+
+func (s *GoogleArtifactRegistrySource) client() (*http.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Client != nil {
+		return s.Client, nil
+	}
+
+	client, err := google.DefaultClient(context.Background(), googleArtifactRegistryScope)
+	if err != nil {
+		return nil, ErrUnauthorized{Hostname: s.hostname(), HaveCredentials: false}
+	}
+	s.Client = client
+	return s.Client, nil
+}
+
+func (s *GoogleArtifactRegistrySource) hostname() svchost.Hostname {
+	hostname, err := svchost.ForComparison(strings.TrimPrefix(s.apiEndpoint(), "https://"))
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+// artifactRegistryPackageName is the conventional Artifact Registry
+// generic package name a provider's packages are expected to be published
+// under.
+func artifactRegistryPackageName(provider addrs.Provider) string {
+	return "terraform-provider-" + provider.Type
+}
@@ -0,0 +1,67 @@
+package getproviders
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestGoogleArtifactRegistrySource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"versions": [
+				{"name": "projects/p/locations/us/repositories/r/packages/terraform-provider-null/versions/1.0.0"},
+				{"name": "projects/p/locations/us/repositories/r/packages/terraform-provider-null/versions/1.1.0"}
+			]
+		}`)
+	}))
+	defer server.Close()
+
+	source := &GoogleArtifactRegistrySource{
+		Project:     "p",
+		Location:    "us",
+		Repository:  "r",
+		Client:      server.Client(),
+		APIEndpoint: server.URL,
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "acme", "null")
+	versions, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(versions) != 2 || versions[0].String() != "1.0.0" || versions[1].String() != "1.1.0" {
+		t.Fatalf("wrong versions: got %v", versions)
+	}
+
+	source.DownloadEndpoint = "https://example-generic.pkg.dev"
+	meta, err := source.PackageMeta(provider, versions[1], Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantURL := PackageHTTPURL("https://example-generic.pkg.dev/projects/p/repositories/r/terraform-provider-null/1.1.0/terraform-provider-null_1.1.0_linux_amd64.zip")
+	if meta.Location != wantURL {
+		t.Errorf("wrong download URL:\ngot:  %s\nwant: %s", meta.Location, wantURL)
+	}
+}
+
+func TestGoogleArtifactRegistrySourceUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	source := &GoogleArtifactRegistrySource{
+		Project: "p", Location: "us", Repository: "r",
+		Client: server.Client(), APIEndpoint: server.URL,
+	}
+	provider := addrs.NewProvider(DefaultRegistryHost, "acme", "null")
+
+	_, err := source.AvailableVersions(provider)
+	if _, ok := err.(ErrUnauthorized); !ok {
+		t.Fatalf("wrong error type %T: %s", err, err)
+	}
+}
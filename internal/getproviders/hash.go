@@ -0,0 +1,103 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HashScheme identifies the algorithm used to produce a hash string
+// recorded as one of a LockedProvider's Hashes.
+type HashScheme string
+
+const (
+	// HashSchemeZip is the legacy hash scheme, which hashes the raw bytes
+	// of a provider's distribution zip file. It cannot be verified against
+	// a filesystem mirror that stores providers unpacked, because the
+	// exact bytes of the original zip file are not preserved there.
+	HashSchemeZip HashScheme = "zh:"
+
+	// HashSchemeDir is the current hash scheme, which hashes the names and
+	// contents of the files produced by unpacking a provider's
+	// distribution package, independently of how that package was
+	// originally archived. It can be verified against both packed and
+	// unpacked mirrors.
+	HashSchemeDir HashScheme = "h1:"
+)
+
+// hasHashScheme returns true if hashes contains at least one hash string
+// using the given scheme.
+func hasHashScheme(hashes []string, scheme HashScheme) bool {
+	prefix := string(scheme)
+	for _, h := range hashes {
+		if strings.HasPrefix(h, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageDirHash computes an HashSchemeDir ("h1:") hash string for the
+// already-unpacked provider package found in the given directory.
+//
+// The result is deterministic regardless of the order in which the
+// filesystem happens to return directory entries, so two directories with
+// identical content produce identical hashes even if they were unpacked by
+// different tools.
+func PackageDirHash(dir string) (string, error) {
+	paths, err := sortedPackageDirFiles(dir)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, rel := range paths {
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", err
+		}
+		fileHash := sha256.New()
+		_, err = io.Copy(fileHash, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%x  %s\n", fileHash.Sum(nil), rel)
+	}
+
+	return "h1:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sortedPackageDirFiles returns the slash-separated, dir-relative paths of
+// every regular file in dir, sorted into a consistent order regardless of
+// what order the filesystem happens to return directory entries in.
+//
+// This is shared by PackageDirHash and RepackUnpacked, since both need to
+// process an unpacked package's files in a filesystem-independent order to
+// produce a deterministic result.
+func sortedPackageDirFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			rel, err := longPathRel(dir, path)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
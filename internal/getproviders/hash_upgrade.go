@@ -0,0 +1,55 @@
+package getproviders
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// UpgradeHashScheme inspects each provider recorded in lock and, for any
+// whose hashes are all in the legacy HashSchemeZip ("zh:") scheme, computes
+// and appends an HashSchemeDir ("h1:") hash by hashing the corresponding
+// already-installed package found in installed.
+//
+// This allows a lock file written before the h1 scheme was introduced to
+// become verifiable against unpacked filesystem mirrors, without requiring
+// the user to manually edit the file or re-download anything: the upgrade
+// is performed by hashing packages that are already present locally.
+//
+// A provider entry is left unchanged if it already has at least one h1:
+// hash, or if installed does not contain a package for that provider at
+// the exact locked version.
+func UpgradeHashScheme(lock *LockFile, installed []Installed) error {
+	byProviderVersion := make(map[addrs.Provider]map[string]Installed, len(installed))
+	for _, inst := range installed {
+		versions, ok := byProviderVersion[inst.Provider]
+		if !ok {
+			versions = make(map[string]Installed)
+			byProviderVersion[inst.Provider] = versions
+		}
+		if _, ok := versions[inst.Version.String()]; !ok {
+			versions[inst.Version.String()] = inst
+		}
+	}
+
+	for provider, locked := range lock.Providers {
+		if hasHashScheme(locked.Hashes, HashSchemeDir) {
+			continue
+		}
+
+		inst, ok := byProviderVersion[provider][locked.Version.String()]
+		if !ok {
+			continue
+		}
+
+		hash, err := PackageDirHash(inst.PackageDir)
+		if err != nil {
+			return fmt.Errorf("failed to compute content hash for %s %s: %s", provider, locked.Version, err)
+		}
+
+		locked.Hashes = append(locked.Hashes, hash)
+		lock.Providers[provider] = locked
+	}
+
+	return nil
+}
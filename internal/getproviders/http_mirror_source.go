@@ -3,13 +3,30 @@ package getproviders
 import (
 	"net/url"
 
+	"golang.org/x/crypto/openpgp"
+
 	"github.com/hashicorp/terraform/addrs"
 )
 
 // HTTPMirrorSource is a source that reads provider metadata from a provider
 // mirror that is accessible over the HTTP provider mirror protocol.
+//
+// HTTPMirrorSource does not implement EnumerableSource: the HTTP mirror
+// protocol's only discovery document is the per-provider index.json the
+// protocol defines, which lists a single provider's versions once its
+// address is already known, not a registry-wide listing of every provider
+// the mirror carries.
 type HTTPMirrorSource struct {
 	baseURL *url.URL
+
+	// TrustedSigningKeys, when non-empty, is the keyring its eventual
+	// implementation should check every downloaded index.json and
+	// "<version>.json" document's detached signature against, using
+	// VerifyMirrorIndexSignature, before trusting its contents -- the
+	// read-side counterpart to SignMirrorIndex on the mirror operator's
+	// side. An empty keyring means the mirror isn't expected to be
+	// signed.
+	TrustedSigningKeys openpgp.EntityList
 }
 
 var _ Source = (*HTTPMirrorSource)(nil)
@@ -23,6 +40,20 @@ func NewHTTPMirrorSource(baseURL *url.URL) *HTTPMirrorSource {
 	}
 }
 
+// NewHTTPMirrorSourceFromDNS is like NewHTTPMirrorSource except that it
+// discovers the mirror's base URL from SRV/TXT records published under the
+// given domain, via DiscoverMirrorURLFromDNS, rather than taking the URL
+// directly. This lets a fleet of machines roam between networks and pick up
+// whichever mirror the local network advertises, without any of them
+// needing mirror configuration of their own.
+func NewHTTPMirrorSourceFromDNS(domain string) (*HTTPMirrorSource, error) {
+	baseURL, err := DiscoverMirrorURLFromDNS(domain)
+	if err != nil {
+		return nil, err
+	}
+	return NewHTTPMirrorSource(baseURL), nil
+}
+
 // AvailableVersions retrieves the available versions for the given provider
 // from the object's underlying HTTP mirror service.
 func (s *HTTPMirrorSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
@@ -0,0 +1,108 @@
+package getproviders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// packageManagerSearchDirs lists the well-known locations where OS package
+// managers such as Homebrew and Chocolatey install executables, so that
+// DetectPackageManagerProviders has somewhere to look without the caller
+// needing to know these conventions.
+var packageManagerSearchDirs = func() []string {
+	switch runtime.GOOS {
+	case "windows":
+		return []string{
+			`C:\ProgramData\chocolatey\bin`,
+			`C:\ProgramData\chocolatey\lib`,
+		}
+	case "darwin":
+		return []string{
+			"/opt/homebrew/bin",
+			"/usr/local/bin",
+		}
+	default:
+		return []string{
+			"/usr/local/bin",
+			"/home/linuxbrew/.linuxbrew/bin",
+		}
+	}
+}()
+
+// packageManagerBinaryPattern matches the unversioned binary names that
+// package managers typically install providers under, such as
+// "terraform-provider-aws", with no embedded version number.
+var packageManagerBinaryPattern = regexp.MustCompile(`^terraform-provider-([a-zA-Z0-9_-]+)(?:\.exe)?$`)
+
+// DetectedProviderBinary describes a provider plugin executable found by
+// DetectPackageManagerProviders in a package manager's install location.
+type DetectedProviderBinary struct {
+	Type string
+	Path string
+}
+
+// DetectPackageManagerProviders scans the well-known install locations used
+// by OS package managers such as Homebrew and Chocolatey for provider
+// plugin executables, returning one result per binary found.
+//
+// Because package-manager-installed binaries have no embedded version or
+// source address metadata, the caller is expected to ask the user to
+// confirm the provider address and version (or to infer the version by
+// executing the binary) before calling ImportPackageManagerProvider.
+func DetectPackageManagerProviders() ([]DetectedProviderBinary, error) {
+	var ret []DetectedProviderBinary
+
+	for _, dir := range packageManagerSearchDirs {
+		entries, err := readDirIfExists(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			m := packageManagerBinaryPattern.FindStringSubmatch(entry.Name())
+			if m == nil {
+				continue
+			}
+			ret = append(ret, DetectedProviderBinary{
+				Type: m[1],
+				Path: filepath.Join(dir, entry.Name()),
+			})
+		}
+	}
+
+	return ret, nil
+}
+
+// ImportPackageManagerProvider copies the executable found at bin.Path into
+// mirrorDir's cache layout as the given provider and version, recording
+// CurrentPlatform as its platform. No content hash is computed for the
+// imported package: the returned Installed carries only its provider,
+// version, platform, and package directory.
+func ImportPackageManagerProvider(bin DetectedProviderBinary, provider addrs.Provider, version Version, mirrorDir string) (Installed, error) {
+	destDir := filepath.Join(
+		mirrorDir, provider.Hostname.String(), provider.Namespace, provider.Type,
+		version.String(), CurrentPlatform.String(),
+	)
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return Installed{}, fmt.Errorf("failed to create %s: %s", destDir, err)
+	}
+
+	destPath := filepath.Join(destDir, filepath.Base(bin.Path))
+	if err := copyFileContents(bin.Path, destPath); err != nil {
+		return Installed{}, fmt.Errorf("failed to copy %s to %s: %s", bin.Path, destPath, err)
+	}
+
+	return Installed{
+		Provider:   provider,
+		Version:    version,
+		Platform:   CurrentPlatform,
+		PackageDir: destDir,
+	}, nil
+}
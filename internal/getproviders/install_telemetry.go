@@ -0,0 +1,123 @@
+package getproviders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// InstallOutcome is the anonymized record InstallTelemetryReporter posts for
+// a single provider installation attempt: enough to tell a platform team
+// which providers, versions, and source types are slow or failing across
+// their fleet, without identifying which configuration, module, or user
+// triggered it.
+type InstallOutcome struct {
+	Provider     string        `json:"provider"`
+	Version      string        `json:"version,omitempty"`
+	SourceType   string        `json:"sourceType"`
+	Duration     time.Duration `json:"durationNanoseconds"`
+	Success      bool          `json:"success"`
+	FailureClass string        `json:"failureClass,omitempty"`
+	RecordedAt   time.Time     `json:"recordedAt"`
+}
+
+// NewInstallOutcome builds the InstallOutcome for a single resolution or
+// install attempt against a provider, classifying err (if any) into one of
+// a small, stable set of FailureClass values using the error types defined
+// in errors.go, so that an analytics backend can group failures without
+// having to parse free-form error text.
+func NewInstallOutcome(provider, version, sourceType string, duration time.Duration, err error) InstallOutcome {
+	outcome := InstallOutcome{
+		Provider:   provider,
+		Version:    version,
+		SourceType: sourceType,
+		Duration:   duration,
+		Success:    err == nil,
+	}
+	if err != nil {
+		outcome.FailureClass = classifyInstallFailure(err)
+	}
+	return outcome
+}
+
+func classifyInstallFailure(err error) string {
+	switch err.(type) {
+	case ErrHostNoProviders:
+		return "host_no_providers"
+	case ErrHostUnreachable:
+		return "host_unreachable"
+	case ErrUnauthorized:
+		return "unauthorized"
+	case ErrProviderNotKnown:
+		return "provider_not_known"
+	case ErrNoSuitableVersion:
+		return "no_suitable_version"
+	case ErrPlatformNotSupported:
+		return "platform_not_supported"
+	case ErrQueryFailed:
+		return "query_failed"
+	default:
+		return "other"
+	}
+}
+
+// InstallTelemetryReporter POSTs InstallOutcome values as JSON to a
+// user-configured endpoint, entirely opt-in: a nil *InstallTelemetryReporter
+// reports nothing, and Report is always safe to call on one even if URL is
+// left unset.
+//
+// This is a self-hosted analytics hook, not a Terraform-operated telemetry
+// service: it's the embedder's responsibility to set URL to an endpoint they
+// control, and nothing here phones home anywhere by default.
+type InstallTelemetryReporter struct {
+	// URL is the endpoint each InstallOutcome is POSTed to as JSON. An
+	// empty URL disables reporting, the same as a nil *InstallTelemetryReporter.
+	URL string
+
+	// HTTPClient is used to make the request. A nil HTTPClient uses
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	// OnError, if non-nil, is called with any error encountered while
+	// delivering an outcome, since Report itself has no return value to
+	// report one through. A nil OnError silently drops delivery failures,
+	// since a telemetry endpoint being unreachable shouldn't be allowed to
+	// interrupt an install.
+	OnError func(error)
+}
+
+// Report delivers outcome to the configured endpoint, doing nothing if r is
+// nil or has no URL configured.
+func (r *InstallTelemetryReporter) Report(outcome InstallOutcome) {
+	if r == nil || r.URL == "" {
+		return
+	}
+	if err := r.deliver(outcome); err != nil && r.OnError != nil {
+		r.OnError(err)
+	}
+}
+
+func (r *InstallTelemetryReporter) deliver(outcome InstallOutcome) error {
+	body, err := json.Marshal(outcome)
+	if err != nil {
+		return err
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Post(r.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("delivering install telemetry to %s: %s", r.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("install telemetry endpoint %s responded with status %s", r.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInstallTelemetryReporter(t *testing.T) {
+	var got InstallOutcome
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode posted outcome: %s", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	reporter := &InstallTelemetryReporter{URL: server.URL}
+	outcome := NewInstallOutcome("registry.terraform.io/hashicorp/null", "3.1.0", "registry", 2*time.Second, nil)
+	reporter.Report(outcome)
+
+	if got.Provider != outcome.Provider {
+		t.Errorf("wrong provider: got %s, want %s", got.Provider, outcome.Provider)
+	}
+	if !got.Success {
+		t.Errorf("wrong success: got false, want true")
+	}
+}
+
+func TestInstallTelemetryReporterFailureClass(t *testing.T) {
+	outcome := NewInstallOutcome("registry.terraform.io/hashicorp/null", "3.1.0", "registry", time.Second, ErrUnauthorized{})
+	if outcome.Success {
+		t.Errorf("expected Success to be false")
+	}
+	if outcome.FailureClass != "unauthorized" {
+		t.Errorf("wrong failure class: got %s, want unauthorized", outcome.FailureClass)
+	}
+}
+
+func TestInstallTelemetryReporterDisabled(t *testing.T) {
+	var reporter *InstallTelemetryReporter
+	reporter.Report(NewInstallOutcome("registry.terraform.io/hashicorp/null", "3.1.0", "registry", time.Second, nil))
+
+	enabled := &InstallTelemetryReporter{}
+	enabled.Report(NewInstallOutcome("registry.terraform.io/hashicorp/null", "3.1.0", "registry", time.Second, nil))
+}
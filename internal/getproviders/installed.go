@@ -0,0 +1,143 @@
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// Installed describes a single provider package that has already been
+// installed into a working directory's provider cache directory.
+type Installed struct {
+	Provider addrs.Provider
+	Version  Version
+	Platform Platform
+
+	// PackageDir is the local filesystem path of the directory containing
+	// the unpacked provider distribution package.
+	PackageDir string
+}
+
+// SearchInstalledProviders scans baseDir -- which is expected to follow the
+// standard provider installation directory layout of
+// baseDir/hostname/namespace/type/version/os_arch -- and returns a
+// description of every provider package it finds there.
+//
+// A provider that has packages installed for more than one target platform
+// produces one Installed result per platform. Any entries in baseDir that
+// do not conform to the expected layout are silently ignored, since the
+// provider cache directory is managed exclusively by Terraform and unknown
+// entries are assumed to belong to some other tool or a future version of
+// Terraform.
+//
+// This function exists so that callers such as the "providers" command,
+// upgrade tooling, and cache cleanup logic can all share a single
+// understanding of the installed provider set, rather than each
+// re-implementing this directory walk with slightly different assumptions.
+func SearchInstalledProviders(baseDir string) ([]Installed, error) {
+	var ret []Installed
+
+	hostEntries, err := readDirIfExists(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installed providers directory %s: %s", baseDir, err)
+	}
+
+	for _, hostEntry := range hostEntries {
+		if !hostEntry.IsDir() {
+			continue
+		}
+		hostname, err := svchost.ForComparison(hostEntry.Name())
+		if err != nil {
+			// Not a valid hostname, so this directory can't be part of
+			// our installation tree.
+			continue
+		}
+		hostDir := filepath.Join(baseDir, hostEntry.Name())
+
+		namespaceEntries, err := readDirIfExists(hostDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", hostDir, err)
+		}
+		for _, namespaceEntry := range namespaceEntries {
+			if !namespaceEntry.IsDir() {
+				continue
+			}
+			namespaceDir := filepath.Join(hostDir, namespaceEntry.Name())
+
+			typeEntries, err := readDirIfExists(namespaceDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", namespaceDir, err)
+			}
+			for _, typeEntry := range typeEntries {
+				if !typeEntry.IsDir() {
+					continue
+				}
+				provider := addrs.NewProvider(hostname, namespaceEntry.Name(), typeEntry.Name())
+				typeDir := filepath.Join(namespaceDir, typeEntry.Name())
+
+				versionEntries, err := readDirIfExists(typeDir)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read %s: %s", typeDir, err)
+				}
+				for _, versionEntry := range versionEntries {
+					if !versionEntry.IsDir() {
+						continue
+					}
+					version, err := ParseVersion(versionEntry.Name())
+					if err != nil {
+						// Not a valid version directory, so skip it.
+						continue
+					}
+					versionDir := filepath.Join(typeDir, versionEntry.Name())
+
+					platformEntries, err := readDirIfExists(versionDir)
+					if err != nil {
+						return nil, fmt.Errorf("failed to read %s: %s", versionDir, err)
+					}
+					for _, platformEntry := range platformEntries {
+						if !platformEntry.IsDir() {
+							continue
+						}
+						platform, err := ParsePlatform(platformEntry.Name())
+						if err != nil {
+							continue
+						}
+
+						ret = append(ret, Installed{
+							Provider:   provider,
+							Version:    version,
+							Platform:   platform,
+							PackageDir: filepath.Join(versionDir, platformEntry.Name()),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+func readDirIfExists(dir string) ([]os.FileInfo, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ret := make([]os.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if isMirrorIgnored(entry.Name()) {
+			continue
+		}
+		ret = append(ret, entry)
+	}
+	return ret, nil
+}
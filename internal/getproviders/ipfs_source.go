@@ -0,0 +1,78 @@
+package getproviders
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// IPFSSource is a Source that retrieves provider packages from IPFS, or any
+// other content-addressed storage gateway that serves content by hash under
+// a "/ipfs/<hash>" path convention, using the package hashes already
+// recorded in a dependency lock file as the lookup key.
+//
+// Content-addressed storage has no notion of a registry's namespace or
+// version listings, so an IPFSSource cannot discover what's available on
+// its own; it can only retrieve a package whose hash a caller already knows,
+// which in practice means one that's already recorded in the lock file from
+// a previous install via a registry or mirror. AvailableVersions therefore
+// always fails, steering callers back towards a regular Source to discover
+// what to install before IPFSSource can be used to fetch it.
+type IPFSSource struct {
+	// GatewayURL is the base URL of the IPFS gateway to fetch packages
+	// from, such as "https://ipfs.io" or an organization's own pinning
+	// gateway. Packages are requested at "<GatewayURL>/ipfs/<hash>".
+	GatewayURL string
+
+	// Hashes maps a provider and version to the HashSchemeZip ("zh:")
+	// content hash recorded for its package in a dependency lock file,
+	// which this source treats as the CID to fetch from GatewayURL.
+	Hashes map[addrs.Provider]map[string]string
+}
+
+// NewIPFSSource constructs an IPFSSource that serves the given already-known
+// provider package hashes from gatewayURL.
+func NewIPFSSource(gatewayURL string, hashes map[addrs.Provider]map[string]string) *IPFSSource {
+	return &IPFSSource{
+		GatewayURL: gatewayURL,
+		Hashes:     hashes,
+	}
+}
+
+var _ Source = (*IPFSSource)(nil)
+
+// AvailableVersions always fails, because content-addressed storage has no
+// way to list what's available; use a RegistrySource or mirror Source for
+// version discovery and record the result in the dependency lock file
+// before using IPFSSource to retrieve it.
+func (s *IPFSSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	return nil, fmt.Errorf("IPFSSource cannot discover available versions for %s; a provider's content hash must already be recorded in the dependency lock file before it can be retrieved from IPFS", provider)
+}
+
+// PackageMeta returns metadata describing where to fetch provider's package
+// for the given version from IPFS, using the hash already recorded in
+// s.Hashes as the lookup key.
+func (s *IPFSSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	hash, ok := s.Hashes[provider][version.String()]
+	if !ok {
+		return PackageMeta{}, fmt.Errorf("no known content hash for %s %s; it must already be recorded in the dependency lock file to be retrieved from IPFS", provider, version)
+	}
+
+	var sha256Sum [32]byte
+	hexDigest := strings.TrimPrefix(hash, string(HashSchemeZip))
+	if hexDigest == hash || len(hexDigest) != len(sha256Sum)*2 {
+		return PackageMeta{}, fmt.Errorf("hash %q recorded for %s %s is not a usable %s content hash", hash, provider, version, HashSchemeZip)
+	}
+	if _, err := hex.Decode(sha256Sum[:], []byte(hexDigest)); err != nil {
+		return PackageMeta{}, fmt.Errorf("hash %q recorded for %s %s is not valid hex: %s", hash, provider, version, err)
+	}
+
+	return PackageMeta{
+		TargetPlatform: target,
+		Filename:       fmt.Sprintf("%s_%s_%s.zip", provider.Type, version, target),
+		Location:       PackageHTTPURL(fmt.Sprintf("%s/ipfs/%s", strings.TrimSuffix(s.GatewayURL, "/"), hexDigest)),
+		SHA256Sum:      sha256Sum,
+	}, nil
+}
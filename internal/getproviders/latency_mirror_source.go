@@ -0,0 +1,137 @@
+package getproviders
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// LatencyMirrorCandidate is one of the equivalent mirrors a
+// LatencyAwareMirrorSource chooses between.
+type LatencyMirrorCandidate struct {
+	// Name identifies this candidate for diagnostic purposes, such as in
+	// the value returned by LatencyAwareMirrorSource.SelectedMirror.
+	Name string
+
+	// Source is the mirror itself, used for every request once this
+	// candidate has been selected.
+	Source Source
+
+	// ProbeURL is a URL that LatencyAwareMirrorSource can issue a cheap GET
+	// request against to measure this candidate's latency and confirm it's
+	// healthy. Mirrors implementing the standard HTTP mirror protocol can
+	// use their index document (e.g. ".well-known/terraform.json" or the
+	// mirror's base URL) for this purpose.
+	ProbeURL string
+}
+
+// LatencyAwareMirrorSource is a Source that wraps a set of mirrors assumed
+// to all serve the same set of providers, probes each of them once, and
+// routes every subsequent request to whichever one responded fastest,
+// instead of always using whichever one happens to be listed first.
+//
+// Candidates that fail to respond to their probe at all are treated as
+// unhealthy and are never selected, even if no other candidate is faster.
+type LatencyAwareMirrorSource struct {
+	candidates []LatencyMirrorCandidate
+	probeFunc  func(probeURL string) (time.Duration, error)
+
+	probeOnce    sync.Once
+	selected     Source
+	selectedName string
+	probeErr     error
+}
+
+// NewLatencyAwareMirrorSource constructs a LatencyAwareMirrorSource over the
+// given candidates. It returns an error immediately if there are no
+// candidates to choose between.
+func NewLatencyAwareMirrorSource(candidates []LatencyMirrorCandidate) (*LatencyAwareMirrorSource, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("must provide at least one mirror candidate")
+	}
+	return &LatencyAwareMirrorSource{
+		candidates: candidates,
+		probeFunc:  probeMirrorLatency,
+	}, nil
+}
+
+var _ Source = (*LatencyAwareMirrorSource)(nil)
+
+// AvailableVersions retrieves the available versions for the given provider
+// from whichever candidate mirror the latency probe selected.
+func (s *LatencyAwareMirrorSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	if err := s.ensureSelected(); err != nil {
+		return nil, err
+	}
+	return s.selected.AvailableVersions(provider)
+}
+
+// PackageMeta retrieves package metadata for the given provider from
+// whichever candidate mirror the latency probe selected.
+func (s *LatencyAwareMirrorSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	if err := s.ensureSelected(); err != nil {
+		return PackageMeta{}, err
+	}
+	return s.selected.PackageMeta(provider, version, target)
+}
+
+// SelectedMirror returns the Name of the candidate the latency probe chose,
+// or the empty string if no request has triggered a probe yet. Callers that
+// produce an install summary can use this to report which mirror actually
+// served a given run.
+func (s *LatencyAwareMirrorSource) SelectedMirror() string {
+	return s.selectedName
+}
+
+func (s *LatencyAwareMirrorSource) ensureSelected() error {
+	s.probeOnce.Do(func() {
+		var bestName string
+		var best Source
+		var bestLatency time.Duration
+		found := false
+
+		for _, candidate := range s.candidates {
+			latency, err := s.probeFunc(candidate.ProbeURL)
+			if err != nil {
+				continue // unhealthy; skip this candidate entirely
+			}
+			if !found || latency < bestLatency {
+				bestName = candidate.Name
+				best = candidate.Source
+				bestLatency = latency
+				found = true
+			}
+		}
+
+		if !found {
+			s.probeErr = fmt.Errorf("none of the %d configured mirrors responded to a health probe", len(s.candidates))
+			return
+		}
+		s.selected = best
+		s.selectedName = bestName
+	})
+	return s.probeErr
+}
+
+// probeMirrorLatency measures how long a GET request against probeURL takes
+// to receive a response, treating any non-2xx status or transport error as
+// the mirror being unhealthy.
+func probeMirrorLatency(probeURL string) (time.Duration, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	start := time.Now()
+	resp, err := client.Get(probeURL)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	elapsed := time.Since(start)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("probe returned %s", resp.Status)
+	}
+	return elapsed, nil
+}
@@ -29,9 +29,9 @@ func LookupLegacyProvider(addr addrs.Provider, source Source) (addrs.Provider, e
 	if addr.Namespace != "-" {
 		return addr, nil
 	}
-	if addr.Hostname != defaultRegistryHost { // condition above assures namespace is also "-"
+	if addr.Hostname != DefaultRegistryHost { // condition above assures namespace is also "-"
 		// Legacy providers must always belong to the default registry host.
-		return addrs.Provider{}, fmt.Errorf("invalid provider type %q: legacy provider addresses must always belong to %s", addr, defaultRegistryHost)
+		return addrs.Provider{}, fmt.Errorf("invalid provider type %q: legacy provider addresses must always belong to %s", addr, DefaultRegistryHost)
 	}
 
 	// Now we need to derive a suitable *RegistrySource from the given source,
@@ -19,7 +19,7 @@ func TestLookupLegacyProvider(t *testing.T) {
 	}
 
 	want := addrs.Provider{
-		Hostname:  defaultRegistryHost,
+		Hostname:  DefaultRegistryHost,
 		Namespace: "legacycorp",
 		Type:      "legacy",
 	}
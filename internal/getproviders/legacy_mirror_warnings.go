@@ -0,0 +1,73 @@
+package getproviders
+
+import (
+	"fmt"
+	"path/filepath"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// LegacyMirrorWarning describes a legacy ("-" namespace) provider entry
+// found during a filesystem mirror scan, of the kind that the older,
+// pre-source-address plugin discovery mechanism used to produce.
+type LegacyMirrorWarning struct {
+	Hostname svchost.Hostname
+	Type     string
+
+	// LikelyAddr is the fully-qualified address this legacy entry probably
+	// corresponds to. It is a guess based on the convention that unqualified
+	// providers on the default registry host belong to the "hashicorp"
+	// namespace, not a confirmed mapping, and is intended for display
+	// purposes only.
+	LikelyAddr addrs.Provider
+}
+
+func (w LegacyMirrorWarning) String() string {
+	return fmt.Sprintf(
+		"legacy-style provider directory %q found under %s likely corresponds to %s; migrate it into a namespaced directory using the mirror layout migration tool",
+		w.Type, w.Hostname.ForDisplay(), w.LikelyAddr,
+	)
+}
+
+// DetectLegacyMirrorEntries scans baseDir's hostname directory structure
+// for legacy-namespace provider entries and returns a structured warning
+// for each one found, so that callers can surface actionable migration
+// guidance instead of only logging a TRACE/WARN message.
+func DetectLegacyMirrorEntries(baseDir string) ([]LegacyMirrorWarning, error) {
+	var ret []LegacyMirrorWarning
+
+	hostEntries, err := readDirIfExists(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", baseDir, err)
+	}
+
+	for _, hostEntry := range hostEntries {
+		if !hostEntry.IsDir() {
+			continue
+		}
+		hostname, err := svchost.ForComparison(hostEntry.Name())
+		if err != nil {
+			continue
+		}
+
+		legacyDir := filepath.Join(baseDir, hostEntry.Name(), addrs.LegacyProviderNamespace)
+		typeEntries, err := readDirIfExists(legacyDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", legacyDir, err)
+		}
+		for _, typeEntry := range typeEntries {
+			if !typeEntry.IsDir() {
+				continue
+			}
+			ret = append(ret, LegacyMirrorWarning{
+				Hostname:   hostname,
+				Type:       typeEntry.Name(),
+				LikelyAddr: addrs.NewProvider(hostname, "hashicorp", typeEntry.Name()),
+			})
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,131 @@
+package getproviders
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// LegacyPluginDirSource is a Source that discovers provider plugin binaries
+// left over from Terraform 0.12's flat terraform.d/plugins directory
+// convention -- terraform-provider-<type>_v<version>, optionally suffixed
+// with a protocol version such as "_x4" and a ".exe" extension -- and
+// serves them as ordinary provider packages.
+//
+// The flat layout predates per-platform subdirectories and was only ever
+// usable from the single machine a binary was placed on, so every package
+// this source reports is implicitly for CurrentPlatform; asking it about
+// any other platform always reports the provider as unsupported there.
+//
+// Because the flat layout has no concept of a registry hostname or
+// namespace either, every provider this source can see is addressed under
+// Namespace at DefaultRegistryHost.
+type LegacyPluginDirSource struct {
+	// Dir is the legacy plugins directory to scan, such as
+	// "~/.terraform.d/plugins".
+	Dir string
+
+	// Namespace is the registry namespace that discovered binaries are
+	// addressed under, since the legacy layout doesn't record one.
+	Namespace string
+}
+
+var _ Source = (*LegacyPluginDirSource)(nil)
+
+// NewLegacyPluginDirSource constructs a new LegacyPluginDirSource that scans
+// dir and addresses any binaries it finds under namespace at
+// DefaultRegistryHost.
+func NewLegacyPluginDirSource(dir, namespace string) *LegacyPluginDirSource {
+	return &LegacyPluginDirSource{
+		Dir:       dir,
+		Namespace: namespace,
+	}
+}
+
+// AvailableVersions returns the versions of provider that this source finds
+// a matching legacy binary for.
+//
+// A provider whose hostname or namespace doesn't match this source's
+// DefaultRegistryHost/Namespace pairing is reported as having no versions
+// available, rather than an error, since that simply means this source
+// isn't the right place to look for it.
+func (s *LegacyPluginDirSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	if provider.Hostname != DefaultRegistryHost || provider.Namespace != s.Namespace {
+		return nil, nil
+	}
+
+	fileEntries, err := readDirIfExists(s.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", s.Dir, err)
+	}
+
+	var ret VersionList
+	for _, fileEntry := range fileEntries {
+		if fileEntry.IsDir() {
+			continue
+		}
+		m := legacyPluginFilenamePattern.FindStringSubmatch(fileEntry.Name())
+		if m == nil || m[1] != provider.Type {
+			continue
+		}
+		version, err := ParseVersion(m[2])
+		if err != nil {
+			continue
+		}
+		ret = append(ret, version)
+	}
+	ret.Sort()
+	return ret, nil
+}
+
+// PackageMeta returns metadata for the legacy binary matching provider and
+// version, if target is CurrentPlatform. Any other target always fails with
+// ErrPlatformNotSupported, since the flat layout has no way to represent a
+// binary for any platform other than the one it's sitting on.
+//
+// The returned PackageMeta's Location is a PackageLocalArchive whose value
+// is, unusually, the legacy binary's own path rather than a zip archive
+// path -- the flat layout never packed its binaries into zips -- so a
+// caller installing from this source must copy that file directly to the
+// expected executable path instead of calling ExtractPackageArchive on it.
+func (s *LegacyPluginDirSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	notSupported := ErrPlatformNotSupported{
+		Provider: provider,
+		Version:  version,
+		Platform: target,
+	}
+	if provider.Hostname != DefaultRegistryHost || provider.Namespace != s.Namespace {
+		return PackageMeta{}, notSupported
+	}
+	if target != CurrentPlatform {
+		return PackageMeta{}, notSupported
+	}
+
+	fileEntries, err := readDirIfExists(s.Dir)
+	if err != nil {
+		return PackageMeta{}, fmt.Errorf("failed to read %s: %s", s.Dir, err)
+	}
+
+	for _, fileEntry := range fileEntries {
+		if fileEntry.IsDir() {
+			continue
+		}
+		m := legacyPluginFilenamePattern.FindStringSubmatch(fileEntry.Name())
+		if m == nil || m[1] != provider.Type {
+			continue
+		}
+		foundVersion, err := ParseVersion(m[2])
+		if err != nil || !foundVersion.Same(version) {
+			continue
+		}
+
+		return PackageMeta{
+			TargetPlatform: target,
+			Filename:       fileEntry.Name(),
+			Location:       PackageLocalArchive(filepath.Join(s.Dir, fileEntry.Name())),
+		}, nil
+	}
+
+	return PackageMeta{}, notSupported
+}
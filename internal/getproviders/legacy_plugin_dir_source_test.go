@@ -0,0 +1,71 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestLegacyPluginDirSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-getproviders-legacy-plugins")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := "terraform-provider-foo_v1.2.0_x4"
+	if err := ioutil.WriteFile(filepath.Join(dir, filename), []byte("pretend binary"), 0755); err != nil {
+		t.Fatalf("failed to write fixture binary: %s", err)
+	}
+
+	source := NewLegacyPluginDirSource(dir, "legacycorp")
+	provider := addrs.NewProvider(DefaultRegistryHost, "legacycorp", "foo")
+
+	gotVersions, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantVersions := VersionList{versionMust(t, "1.2.0")}
+	if !reflect.DeepEqual(gotVersions, wantVersions) {
+		t.Errorf("wrong versions\ngot:  %#v\nwant: %#v", gotVersions, wantVersions)
+	}
+
+	meta, err := source.PackageMeta(provider, versionMust(t, "1.2.0"), CurrentPlatform)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantLocation := PackageLocalArchive(filepath.Join(dir, filename))
+	if meta.Location != wantLocation {
+		t.Errorf("wrong location\ngot:  %#v\nwant: %#v", meta.Location, wantLocation)
+	}
+
+	otherPlatform := Platform{OS: "nonexistent", Arch: "nonexistent"}
+	if _, err := source.PackageMeta(provider, versionMust(t, "1.2.0"), otherPlatform); err == nil {
+		t.Fatalf("expected an error for a platform other than CurrentPlatform")
+	} else if _, ok := err.(ErrPlatformNotSupported); !ok {
+		t.Errorf("wrong error type %T, want ErrPlatformNotSupported", err)
+	}
+}
+
+func TestLegacyPluginDirSourceUnrelatedProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-getproviders-legacy-plugins")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := NewLegacyPluginDirSource(dir, "legacycorp")
+	provider := addrs.NewProvider(DefaultRegistryHost, "othernamespace", "foo")
+
+	got, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no versions for an unrelated namespace, got %s", got)
+	}
+}
@@ -0,0 +1,178 @@
+package getproviders
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// LockFile represents the recorded set of provider packages that a
+// particular working directory was last initialized with, keyed by
+// provider address.
+//
+// This is currently an in-memory representation only. Serialization to and
+// from an on-disk lock file format will be added in a later change, but
+// logic that needs to reason about locked provider selections can already
+// be built against this representation.
+type LockFile struct {
+	Providers map[addrs.Provider]LockedProvider
+}
+
+// LockedProvider is a single entry in a LockFile, recording the exact
+// package that was selected for a particular provider the last time the
+// working directory was initialized.
+type LockedProvider struct {
+	Version Version
+
+	// Hashes are zero or more opaque checksum strings that were recorded
+	// as acceptable for this provider version at the time it was locked.
+	// The meaning of each string is determined by its scheme prefix, such
+	// as "h1:" or "zh:".
+	Hashes []string
+
+	// Signature is an optional ASCII-armored OpenPGP detached signature
+	// covering the canonical text produced by SignableText, allowing a team
+	// to sign their lock file (or individual entries within it) with a
+	// shared key so that a change to pinned hashes from a compromised
+	// developer laptop can be detected rather than silently trusted.
+	Signature string
+
+	// AuthenticationLevel records how strongly this package's origin was
+	// verified the last time it was installed, so that a later install
+	// achieving a weaker level can be detected rather than silently
+	// trusted just because the hashes still match.
+	AuthenticationLevel AuthenticationLevel
+}
+
+// AuthenticationLevel describes how strongly a provider package's origin
+// was verified at install time, ordered from weakest to strongest so that
+// levels can be compared with Go's usual relational operators.
+type AuthenticationLevel int
+
+const (
+	// AuthenticationLevelUnauthenticated means the package was installed
+	// without any verification at all, such as from an unauthenticated
+	// local filesystem mirror.
+	AuthenticationLevelUnauthenticated AuthenticationLevel = iota
+
+	// AuthenticationLevelVerifiedChecksum means the package's checksum was
+	// confirmed to match a value recorded somewhere -- a registry response
+	// or a mirror's checksums file -- but that value was not itself signed
+	// by a trusted key.
+	AuthenticationLevelVerifiedChecksum
+
+	// AuthenticationLevelSignedByThirdParty means the package's checksum
+	// was confirmed to match a value in a document signed by a trusted key
+	// belonging to someone other than HashiCorp, such as a private
+	// registry's own signing key.
+	AuthenticationLevelSignedByThirdParty
+
+	// AuthenticationLevelSignedByHashiCorp means the package's checksum
+	// was confirmed to match a value in a document signed by HashiCorp's
+	// own provider signing key, the strongest level of assurance the
+	// public registry can offer.
+	AuthenticationLevelSignedByHashiCorp
+)
+
+func (l AuthenticationLevel) String() string {
+	switch l {
+	case AuthenticationLevelSignedByHashiCorp:
+		return "signed by HashiCorp"
+	case AuthenticationLevelSignedByThirdParty:
+		return "signed by a third-party key"
+	case AuthenticationLevelVerifiedChecksum:
+		return "verified checksum"
+	default:
+		return "unauthenticated"
+	}
+}
+
+// SignableText returns the canonical text that Signature is expected to
+// be a detached signature of. It is a simple deterministic rendering of
+// the version and hashes so that signing and verification agree on exactly
+// what was signed regardless of how the lock file itself is serialized.
+func (p LockedProvider) SignableText(provider addrs.Provider) string {
+	text := fmt.Sprintf("%s %s\n", provider.String(), p.Version.String())
+	for _, hash := range p.Hashes {
+		text += hash + "\n"
+	}
+	return text
+}
+
+// VerifySignature checks that Signature is a valid detached signature of
+// SignableText made by a key in the given ASCII-armored keyring, returning
+// an error if the entry is unsigned or the signature does not check out.
+func (p LockedProvider) VerifySignature(provider addrs.Provider, armoredKeyring string) error {
+	if p.Signature == "" {
+		return fmt.Errorf("provider %s has no recorded signature", provider)
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKeyring))
+	if err != nil {
+		return fmt.Errorf("invalid signing keyring: %s", err)
+	}
+
+	block, err := armor.Decode(strings.NewReader(p.Signature))
+	if err != nil {
+		return fmt.Errorf("provider %s has an invalid signature: %s", provider, err)
+	}
+
+	_, err = openpgp.CheckDetachedSignature(
+		keyring,
+		strings.NewReader(p.SignableText(provider)),
+		block.Body,
+	)
+	if err != nil {
+		return fmt.Errorf("signature verification failed for provider %s: %s", provider, err)
+	}
+	return nil
+}
+
+// NewLockFile constructs an empty LockFile, ready to have providers added
+// to it.
+func NewLockFile() *LockFile {
+	return &LockFile{
+		Providers: make(map[addrs.Provider]LockedProvider),
+	}
+}
+
+// HasProviderVersion returns true if the lock file has an entry for the
+// given provider at exactly the given version.
+func (f *LockFile) HasProviderVersion(provider addrs.Provider, version Version) bool {
+	if f == nil {
+		return false
+	}
+	locked, ok := f.Providers[provider]
+	if !ok {
+		return false
+	}
+	return locked.Version.Same(version)
+}
+
+// RecordAuthentication updates provider's entry with the authentication
+// level achieved while installing it, creating the entry if necessary.
+//
+// If the provider already had a recorded authentication level that was
+// stronger than level, RecordAuthentication leaves the weaker level in
+// place anyway -- trusting the install that just happened -- but returns a
+// non-empty warning describing the downgrade, so the caller can surface it
+// to the user instead of silently trusting a weaker verification than a
+// previous install achieved.
+func (f *LockFile) RecordAuthentication(provider addrs.Provider, version Version, level AuthenticationLevel) (warning string) {
+	locked := f.Providers[provider]
+	if locked.AuthenticationLevel > level {
+		warning = fmt.Sprintf(
+			"provider %s was previously installed with %s, but this install only achieved %s",
+			provider, locked.AuthenticationLevel, level,
+		)
+	}
+
+	locked.Version = version
+	locked.AuthenticationLevel = level
+	f.Providers[provider] = locked
+	return warning
+}
@@ -0,0 +1,121 @@
+package getproviders
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// LockFileUpgradeRequest describes one provider to consider upgrading as
+// part of a call to PlanLockFileUpgrades: the constraint its newest
+// allowed version must satisfy, and the platforms whose package hashes
+// should be recorded into the updated lock entry.
+type LockFileUpgradeRequest struct {
+	Provider    addrs.Provider
+	Constraints VersionConstraints
+	Platforms   []Platform
+}
+
+// LockFileUpgrade records a single provider's move from one locked version
+// to another, in the form a changelog or upgrade-PR bot can render
+// directly.
+type LockFileUpgrade struct {
+	Provider addrs.Provider
+
+	// HadOldVersion is false if the provider had no previous lock entry at
+	// all, in which case OldVersion is the zero Version and should not be
+	// rendered as part of the change.
+	HadOldVersion bool
+	OldVersion    Version
+
+	NewVersion Version
+}
+
+// LockFileUpgradePlan is the outcome of a call to PlanLockFileUpgrades: the
+// set of bumps it proposes, in the same order as the requests that
+// produced them.
+type LockFileUpgradePlan struct {
+	Upgrades []LockFileUpgrade
+}
+
+// PlanLockFileUpgrades computes the newest version each of reqs allows,
+// given the current versions available from source, and returns both an
+// updated LockFile reflecting those selections (with hashes recorded for
+// every requested platform) and a LockFileUpgradePlan describing what
+// changed, suitable for rendering as a machine-readable changelog.
+//
+// Providers already locked at the newest allowed version are left
+// untouched in the returned LockFile and do not appear in the plan.
+// Entries in lock for providers not mentioned in reqs are carried over to
+// the returned LockFile unmodified. The original lock is never modified.
+func PlanLockFileUpgrades(source Source, lock *LockFile, reqs []LockFileUpgradeRequest) (*LockFile, LockFileUpgradePlan, error) {
+	updated := NewLockFile()
+	for provider, locked := range lock.Providers {
+		updated.Providers[provider] = locked
+	}
+
+	var plan LockFileUpgradePlan
+	for _, req := range reqs {
+		newVersion, err := newestAllowedVersion(source, req.Provider, req.Constraints)
+		if err != nil {
+			return nil, LockFileUpgradePlan{}, err
+		}
+
+		old, hadOld := lock.Providers[req.Provider]
+		if hadOld && old.Version.Same(newVersion) {
+			continue
+		}
+
+		hashes, err := platformHashes(source, req.Provider, newVersion, req.Platforms)
+		if err != nil {
+			return nil, LockFileUpgradePlan{}, err
+		}
+
+		entry := updated.Providers[req.Provider]
+		entry.Version = newVersion
+		entry.Hashes = hashes
+		updated.Providers[req.Provider] = entry
+
+		plan.Upgrades = append(plan.Upgrades, LockFileUpgrade{
+			Provider:      req.Provider,
+			HadOldVersion: hadOld,
+			OldVersion:    old.Version,
+			NewVersion:    newVersion,
+		})
+	}
+
+	return updated, plan, nil
+}
+
+func newestAllowedVersion(source Source, provider addrs.Provider, vc VersionConstraints) (Version, error) {
+	acceptable := MeetingVersionConstraints(vc)
+
+	var newest Version
+	found := false
+	err := StreamAvailableVersions(source, provider, func(v Version) bool {
+		if acceptable.Has(v) {
+			newest = v
+			found = true
+		}
+		return true // keep consuming so we end up with the newest match
+	})
+	if err != nil {
+		return Version{}, err
+	}
+	if !found {
+		return Version{}, ErrNoSuitableVersion{Provider: provider, Constraints: vc}
+	}
+	return newest, nil
+}
+
+func platformHashes(source Source, provider addrs.Provider, version Version, platforms []Platform) ([]string, error) {
+	hashes := make([]string, 0, len(platforms))
+	for _, platform := range platforms {
+		meta, err := source.PackageMeta(provider, version, platform)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get package metadata for %s %s on %s: %s", provider, version, platform, err)
+		}
+		hashes = append(hashes, fmt.Sprintf("%s%x", HashSchemeZip, meta.SHA256Sum))
+	}
+	return hashes, nil
+}
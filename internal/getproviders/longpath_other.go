@@ -0,0 +1,17 @@
+// +build !windows
+
+package getproviders
+
+import "path/filepath"
+
+// toLongPath is a no-op on platforms without Windows' legacy MAX_PATH
+// limitation.
+func toLongPath(path string) string {
+	return path
+}
+
+// longPathRel is equivalent to filepath.Rel on platforms with no
+// extended-length path prefix to account for.
+func longPathRel(base, target string) (string, error) {
+	return filepath.Rel(base, target)
+}
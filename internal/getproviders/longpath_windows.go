@@ -0,0 +1,61 @@
+// +build windows
+
+package getproviders
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// longPathPrefix is prepended to an absolute drive-letter path to opt it
+// out of Windows' legacy MAX_PATH (260 character) limit, per the \\?\
+// extended-length path convention the Windows API understands but normal
+// path syntax doesn't.
+const longPathPrefix = `\\?\`
+
+// longPathUNCPrefix is the \\?\UNC\ form required to extended-length-escape
+// a UNC path (\\server\share\...) instead of a drive-letter path, since the
+// plain \\?\ prefix would otherwise be misinterpreted as the start of
+// another UNC path rather than an escape of one.
+const longPathUNCPrefix = `\\?\UNC\`
+
+// toLongPath prepends the appropriate \\?\ extended-length prefix to path,
+// if it isn't already present, so that scanning a deeply nested mirror --
+// baseDir/hostname/namespace/type/version/os_arch can easily exceed 260
+// characters -- doesn't fail with MAX_PATH.
+//
+// path must already be absolute; toLongPath returns it unchanged if it
+// isn't, since the extended-length prefix only has meaning for absolute
+// paths.
+func toLongPath(path string) string {
+	if strings.HasPrefix(path, longPathPrefix) {
+		return path
+	}
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	if strings.HasPrefix(path, `\\`) {
+		return longPathUNCPrefix + strings.TrimPrefix(path, `\\`)
+	}
+	return longPathPrefix + path
+}
+
+// stripLongPath removes whichever \\?\ extended-length prefix toLongPath
+// may have added, restoring a path that filepath.Rel and similar
+// volume-name-sensitive comparisons can work with, since a \\?\C:\foo path
+// and a plain C:\foo path referring to the same file otherwise compare as
+// having different volume names and so never produce a relative path
+// between them.
+func stripLongPath(path string) string {
+	if rest := strings.TrimPrefix(path, longPathUNCPrefix); rest != path {
+		return `\\` + rest
+	}
+	return strings.TrimPrefix(path, longPathPrefix)
+}
+
+// longPathRel is a UNC- and extended-length-path-aware equivalent of
+// filepath.Rel, for comparing paths that may or may not have been passed
+// through toLongPath.
+func longPathRel(base, target string) (string, error) {
+	return filepath.Rel(stripLongPath(base), stripLongPath(target))
+}
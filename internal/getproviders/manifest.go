@@ -0,0 +1,93 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/apparentlymart/go-versions/versions"
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// ManifestEntry is a single line of a requirements manifest: a provider
+// address and the version constraints that should be applied to it when
+// warming a cache or mirror with WarmCacheFromManifest.
+type ManifestEntry struct {
+	Provider    addrs.Provider
+	Constraints string
+}
+
+// manifestJSON is the on-disk representation of ManifestEntry, using plain
+// strings so that a manifest can be hand-written or generated without any
+// dependency on Terraform's own types.
+type manifestJSON struct {
+	Provider    string `json:"provider"`
+	Constraints string `json:"constraints"`
+}
+
+// ParseManifest reads a requirements manifest: a JSON array of objects each
+// giving a provider source address and a Ruby-style version constraint
+// string, such as:
+//
+//	[
+//	  {"provider": "registry.terraform.io/hashicorp/aws", "constraints": ">= 3.0"},
+//	  {"provider": "registry.terraform.io/hashicorp/null", "constraints": "2.1.2"}
+//	]
+//
+// This format exists so that a cache or mirror can be pre-warmed by tooling
+// that has no Terraform configuration to read requirements from at all, such
+// as an image-build pipeline assembling a base image before any Terraform
+// code has been checked out.
+func ParseManifest(r io.Reader) ([]ManifestEntry, error) {
+	var raw []manifestJSON
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid requirements manifest: %s", err)
+	}
+
+	entries := make([]ManifestEntry, len(raw))
+	for i, item := range raw {
+		provider, diags := addrs.ParseProviderSourceString(item.Provider)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("invalid provider address %q: %s", item.Provider, diags.Err())
+		}
+		entries[i] = ManifestEntry{
+			Provider:    provider,
+			Constraints: item.Constraints,
+		}
+	}
+	return entries, nil
+}
+
+// Acceptable parses the entry's Constraints and returns a function suitable
+// for use as a ResolutionRequest's Acceptable field. An empty Constraints
+// string accepts every version.
+func (e ManifestEntry) Acceptable() (func(Version) bool, error) {
+	if e.Constraints == "" {
+		return nil, nil
+	}
+	set, err := versions.MeetingConstraintsStringRuby(e.Constraints)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraints %q for %s: %s", e.Constraints, e.Provider, err)
+	}
+	return set.Has, nil
+}
+
+// WarmCacheFromManifest resolves and returns the packages selected for each
+// entry of a requirements manifest, so that a caller can download them into
+// a mirror or cache ahead of time, without needing any Terraform
+// configuration to derive requirements from.
+func WarmCacheFromManifest(source Source, entries []ManifestEntry, target Platform) ([]ResolutionResult, error) {
+	reqs := make([]ResolutionRequest, len(entries))
+	for i, entry := range entries {
+		acceptable, err := entry.Acceptable()
+		if err != nil {
+			return nil, err
+		}
+		reqs[i] = ResolutionRequest{
+			Provider:   entry.Provider,
+			Acceptable: acceptable,
+			Target:     target,
+		}
+	}
+	return ResolvePackages(source, reqs), nil
+}
@@ -0,0 +1,118 @@
+package getproviders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+)
+
+// MessageID identifies one of the user-facing messages an installer or CLI
+// command shows in the course of installing a provider, independently of
+// the language or exact wording used for it.
+type MessageID string
+
+const (
+	MessageProviderNotFound    MessageID = "provider_not_found"
+	MessageHostUnreachable     MessageID = "host_unreachable"
+	MessageUnauthorized        MessageID = "unauthorized"
+	MessagePlatformUnsupported MessageID = "platform_unsupported"
+	MessageInstallSucceeded    MessageID = "install_succeeded"
+)
+
+// MessageData is the template data available to every message in a
+// MessageCatalog.
+type MessageData struct {
+	Provider string
+	Version  string
+	Source   string
+
+	// Remediation is optional org- or deployment-specific guidance, such as
+	// "request access via the internal provider mirror", appended to the
+	// rendered message by whichever templates choose to reference it. It's
+	// supplied by the caller at render time rather than baked into a
+	// catalog, so the same catalog works whether or not a given install
+	// attempt has remediation text available.
+	Remediation string
+}
+
+// MessageCatalog maps a MessageID to the Go template used to render it
+// against a MessageData, so that the text an installer shows for each
+// situation -- to translate it, or to fold in enterprise-specific
+// remediation text -- can be replaced without forking the installer.
+//
+// A MessageCatalog built by DefaultMessageCatalog has every MessageID
+// populated; a partial catalog, such as one loaded from a translator's
+// file that only covers the messages they've gotten to so far, is meant to
+// be combined with it using Merge so that rendering never fails for lack
+// of a template.
+type MessageCatalog map[MessageID]*template.Template
+
+// DefaultMessageCatalog returns the built-in English-language message
+// catalog.
+func DefaultMessageCatalog() MessageCatalog {
+	return MessageCatalog{
+		MessageProviderNotFound:    mustParseMessage(MessageProviderNotFound, `provider {{.Provider}} was not found{{if .Source}} in {{.Source}}{{end}}.{{if .Remediation}} {{.Remediation}}{{end}}`),
+		MessageHostUnreachable:     mustParseMessage(MessageHostUnreachable, `could not reach the registry for provider {{.Provider}}.{{if .Remediation}} {{.Remediation}}{{end}}`),
+		MessageUnauthorized:        mustParseMessage(MessageUnauthorized, `not authorized to download provider {{.Provider}}{{if .Version}} {{.Version}}{{end}}.{{if .Remediation}} {{.Remediation}}{{end}}`),
+		MessagePlatformUnsupported: mustParseMessage(MessagePlatformUnsupported, `provider {{.Provider}} {{.Version}} is not available for this platform.{{if .Remediation}} {{.Remediation}}{{end}}`),
+		MessageInstallSucceeded:    mustParseMessage(MessageInstallSucceeded, `installed provider {{.Provider}} {{.Version}}{{if .Source}} from {{.Source}}{{end}}.`),
+	}
+}
+
+func mustParseMessage(id MessageID, tmpl string) *template.Template {
+	return template.Must(template.New(string(id)).Parse(tmpl))
+}
+
+// LoadMessageCatalogJSON parses data as a JSON object mapping MessageID
+// strings to Go template source, for loading a translated or customized
+// catalog from a file rather than constructing one in Go.
+//
+// The result is typically passed to Merge against DefaultMessageCatalog so
+// that a message this catalog doesn't cover still falls back to the
+// built-in English text instead of failing to render at all.
+func LoadMessageCatalogJSON(data []byte) (MessageCatalog, error) {
+	var raw map[MessageID]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid message catalog: %s", err)
+	}
+
+	catalog := make(MessageCatalog, len(raw))
+	for id, tmplSrc := range raw {
+		tmpl, err := template.New(string(id)).Parse(tmplSrc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for message %s: %s", id, err)
+		}
+		catalog[id] = tmpl
+	}
+	return catalog, nil
+}
+
+// Merge returns a new MessageCatalog containing every entry of c, with each
+// entry of overrides replacing c's entry of the same MessageID, if any.
+func (c MessageCatalog) Merge(overrides MessageCatalog) MessageCatalog {
+	merged := make(MessageCatalog, len(c)+len(overrides))
+	for id, tmpl := range c {
+		merged[id] = tmpl
+	}
+	for id, tmpl := range overrides {
+		merged[id] = tmpl
+	}
+	return merged
+}
+
+// Render renders the template registered for id against data, returning an
+// error if the catalog has no template for id or the template fails to
+// execute.
+func (c MessageCatalog) Render(id MessageID, data MessageData) (string, error) {
+	tmpl, ok := c[id]
+	if !ok {
+		return "", fmt.Errorf("no message registered for %s", id)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render message %s: %s", id, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,62 @@
+package getproviders
+
+import "testing"
+
+func TestDefaultMessageCatalogRender(t *testing.T) {
+	catalog := DefaultMessageCatalog()
+
+	got, err := catalog.Render(MessageUnauthorized, MessageData{
+		Provider:    "registry.terraform.io/hashicorp/null",
+		Version:     "3.1.0",
+		Remediation: "request access via the internal provider mirror",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "not authorized to download provider registry.terraform.io/hashicorp/null 3.1.0. request access via the internal provider mirror"
+	if got != want {
+		t.Errorf("wrong message:\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestMessageCatalogRenderUnknown(t *testing.T) {
+	catalog := DefaultMessageCatalog()
+	if _, err := catalog.Render(MessageID("does_not_exist"), MessageData{}); err == nil {
+		t.Fatalf("expected error for an unknown message id")
+	}
+}
+
+func TestLoadMessageCatalogJSONMerge(t *testing.T) {
+	overrides, err := LoadMessageCatalogJSON([]byte(`{"unauthorized": "acceso denegado para {{.Provider}}"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	catalog := DefaultMessageCatalog().Merge(overrides)
+
+	got, err := catalog.Render(MessageUnauthorized, MessageData{Provider: "registry.terraform.io/hashicorp/null"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != "acceso denegado para registry.terraform.io/hashicorp/null" {
+		t.Errorf("wrong message: %s", got)
+	}
+
+	// A message the overrides don't cover still falls back to the default.
+	got, err = catalog.Render(MessageInstallSucceeded, MessageData{Provider: "registry.terraform.io/hashicorp/null", Version: "3.1.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != "installed provider registry.terraform.io/hashicorp/null 3.1.0." {
+		t.Errorf("wrong fallback message: %s", got)
+	}
+}
+
+func TestLoadMessageCatalogJSONInvalid(t *testing.T) {
+	if _, err := LoadMessageCatalogJSON([]byte(`not json`)); err == nil {
+		t.Fatalf("expected error for invalid JSON")
+	}
+	if _, err := LoadMessageCatalogJSON([]byte(`{"unauthorized": "{{.Bad"}`)); err == nil {
+		t.Fatalf("expected error for an invalid template")
+	}
+}
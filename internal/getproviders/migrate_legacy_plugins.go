@@ -0,0 +1,109 @@
+package getproviders
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// legacyPluginFilenamePattern matches the old plugin binary naming
+// convention of terraform-provider-<type>_v<version>, with an optional
+// trailing "_x<n>" protocol version suffix and ".exe" extension.
+var legacyPluginFilenamePattern = regexp.MustCompile(`^terraform-provider-([a-zA-Z0-9_-]+)_v(.+?)(?:_x\d+)?(?:\.exe)?$`)
+
+// MigrateLegacyPluginDir copies provider plugin binaries laid out using the
+// old terraform.d/plugins/<os>_<arch>/terraform-provider-<type>_v<version>
+// convention into mirrorDir, using the hostname/namespace/type/version/
+// os_arch layout that FilesystemMirrorSource expects.
+//
+// Because the legacy layout has no concept of a registry hostname or
+// namespace, every migrated provider is placed under defaultNamespace at
+// DefaultRegistryHost. Callers should review the result, since some of the
+// providers originally discovered this way may really have come from a
+// different namespace.
+//
+// This exists so that long-time users who have accumulated plugins in
+// terraform.d/plugins can adopt filesystem mirrors without having to
+// manually shuffle files into the new directory structure.
+func MigrateLegacyPluginDir(legacyDir, mirrorDir, defaultNamespace string) ([]addrs.Provider, error) {
+	var migrated []addrs.Provider
+
+	platformEntries, err := readDirIfExists(legacyDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", legacyDir, err)
+	}
+
+	for _, platformEntry := range platformEntries {
+		if !platformEntry.IsDir() {
+			continue
+		}
+		platform, err := ParsePlatform(platformEntry.Name())
+		if err != nil {
+			// Not a recognized $OS_$ARCH directory, so it can't be part of
+			// the legacy plugin layout.
+			continue
+		}
+		platformDir := filepath.Join(legacyDir, platformEntry.Name())
+
+		fileEntries, err := readDirIfExists(platformDir)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to read %s: %s", platformDir, err)
+		}
+		for _, fileEntry := range fileEntries {
+			m := legacyPluginFilenamePattern.FindStringSubmatch(fileEntry.Name())
+			if m == nil {
+				continue
+			}
+			typeName, versionStr := m[1], m[2]
+			version, err := ParseVersion(versionStr)
+			if err != nil {
+				continue
+			}
+
+			provider := addrs.NewProvider(DefaultRegistryHost, defaultNamespace, typeName)
+			destDir := filepath.Join(
+				mirrorDir, provider.Hostname.String(), provider.Namespace, provider.Type,
+				version.String(), platform.String(),
+			)
+			if err := os.MkdirAll(destDir, 0755); err != nil {
+				return migrated, fmt.Errorf("failed to create %s: %s", destDir, err)
+			}
+
+			srcPath := filepath.Join(platformDir, fileEntry.Name())
+			destPath := filepath.Join(destDir, fileEntry.Name())
+			if err := copyFileContents(srcPath, destPath); err != nil {
+				return migrated, fmt.Errorf("failed to copy %s to %s: %s", srcPath, destPath, err)
+			}
+
+			migrated = append(migrated, provider)
+		}
+	}
+
+	return migrated, nil
+}
+
+func copyFileContents(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
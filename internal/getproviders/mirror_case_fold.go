@@ -0,0 +1,110 @@
+package getproviders
+
+import (
+	"os"
+	"path/filepath"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// resolveMirrorTypeDir finds the on-disk directory that holds provider's
+// packages under baseDir. That's ordinarily just
+// baseDir/hostname/namespace/type, the literal path PackageMeta used to
+// construct directly, so that join is tried first. It fails, though, for a
+// provider whose directory names on disk differ from the address's own
+// normalized hostname/namespace/type only in case -- which a mirror
+// assembled on a case-insensitive filesystem such as macOS's default one
+// commonly produces. Scanning still recognizes such a provider, because it
+// normalizes each directory entry's name as it walks it, but a literal path
+// join does not. resolveMirrorTypeDir falls back to that same per-level,
+// normalize-and-compare search whenever the direct join doesn't exist, so
+// that PackageMeta can still find what AvailableVersions already reported.
+func resolveMirrorTypeDir(baseDir string, provider addrs.Provider) (string, bool) {
+	direct := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+	if info, err := os.Stat(direct); err == nil && info.IsDir() {
+		return direct, true
+	}
+
+	hostDir, ok := findMirrorChildDir(baseDir, func(name string) bool {
+		hostname, err := svchost.ForComparison(name)
+		return err == nil && hostname == provider.Hostname
+	})
+	if !ok {
+		return "", false
+	}
+	namespaceDir, ok := findMirrorChildDir(hostDir, func(name string) bool {
+		part, err := addrs.ParseProviderPart(name)
+		return err == nil && part == provider.Namespace
+	})
+	if !ok {
+		return "", false
+	}
+	return findMirrorChildDir(namespaceDir, func(name string) bool {
+		part, err := addrs.ParseProviderPart(name)
+		return err == nil && part == provider.Type
+	})
+}
+
+// findMirrorChildDir returns the path of the first direct subdirectory of
+// dir whose name satisfies matches, or ok=false if dir doesn't exist or none
+// of its subdirectories do.
+func findMirrorChildDir(dir string, matches func(name string) bool) (string, bool) {
+	entries, err := readDirIfExists(dir)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && matches(entry.Name()) {
+			return filepath.Join(dir, entry.Name()), true
+		}
+	}
+	return "", false
+}
+
+// mergeVersionLists returns the de-duplicated union of a and b, without
+// assuming either is already sorted.
+//
+// This is needed because two differently-cased directory entries -- such as
+// "HashiCorp" and "hashicorp" namespace directories both present in the
+// same mirror -- normalize to the same provider address, and scanning must
+// report the union of what each one has rather than letting the second one
+// scanned silently replace the first in the result map.
+func mergeVersionLists(a, b VersionList) VersionList {
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make(VersionList, 0, len(a)+len(b))
+	for _, list := range []VersionList{a, b} {
+		for _, v := range list {
+			key := v.String()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
+
+// addMirrorScanResult records versions as the result of scanning a single
+// provider type directory into ret, merging with whatever ret already has
+// for provider rather than letting a second differently-cased directory
+// entry that normalizes to the same provider silently overwrite the first.
+// versions is left empty-safe to call with: a nil or empty versions is a
+// no-op. The stored VersionList is always sorted.
+//
+// Every scan variant -- serial, concurrent, strict, and symlink-following
+// -- funnels its per-type-directory result through this one function so
+// that the case-merging behavior can't independently drift out of sync
+// between them again.
+func addMirrorScanResult(ret map[addrs.Provider]VersionList, provider addrs.Provider, versions VersionList) {
+	if len(versions) == 0 {
+		return
+	}
+	if existing, ok := ret[provider]; ok {
+		versions = mergeVersionLists(existing, versions)
+	}
+	versions.Sort()
+	ret[provider] = versions
+}
@@ -0,0 +1,63 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestScanMirrorPackagesMergesCaseVariants(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-getproviders-casefold")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "HashiCorp", "aws", "1.0.0", "linux_amd64"))
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "aws", "2.0.0", "linux_amd64"))
+
+	got, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "aws")
+	versions, ok := got[provider]
+	if !ok {
+		t.Fatalf("no entry for %s in %#v", provider, got)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("wrong number of merged versions: got %d, want 2: %#v", len(versions), versions)
+	}
+}
+
+func TestFilesystemMirrorSourcePackageMetaCaseInsensitive(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-getproviders-casefold")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "HashiCorp", "AWS", "1.0.0", "linux_amd64"))
+
+	source := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "aws")
+
+	meta, err := source.PackageMeta(provider, versionMust(t, "1.0.0"), Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if meta.Filename == "" {
+		t.Errorf("expected a non-empty filename")
+	}
+}
+
+func mustMkdirAll(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %s", dir, err)
+	}
+}
@@ -0,0 +1,110 @@
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// ConvertedPackage describes one packed provider package
+// ConvertPackedToUnpacked extracted into the unpacked directory layout.
+type ConvertedPackage struct {
+	Provider addrs.Provider
+	Version  Version
+	Platform Platform
+}
+
+// ConvertPackedToUnpacked walks baseDir's mirror layout and extracts every
+// packed .zip archive it finds into the unpacked directory layout
+// alongside it, so a mirror maintainer can switch a mirror from packed to
+// unpacked distribution without a hand-written shell script.
+//
+// Each archive is extracted into a temporary directory created next to its
+// eventual home and then moved into place with a single os.Rename, so a
+// concurrent reader never observes a partially-extracted package: it either
+// still sees the packed zip, the renamed directory hasn't appeared yet, or
+// the directory is already complete. Only after that rename succeeds is
+// the original packed archive (and its ".sha256" sidecar, if present)
+// removed. ExtractPackageArchive, which this uses to do the extraction
+// itself, is what guards against zip-slip entries escaping the
+// destination directory.
+//
+// It returns a ConvertedPackage for each archive it successfully converted;
+// a package already present in unpacked form is left untouched.
+func ConvertPackedToUnpacked(baseDir string) ([]ConvertedPackage, error) {
+	packages, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var converted []ConvertedPackage
+	for provider := range packages {
+		typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+		entries, err := readDirIfExists(typeDir)
+		if err != nil {
+			return converted, fmt.Errorf("failed to read %s: %s", typeDir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			m := packageFilenamePattern.FindStringSubmatch(entry.Name())
+			if m == nil {
+				continue
+			}
+			version, err := ParseVersion(m[2])
+			if err != nil {
+				continue
+			}
+			platform := Platform{OS: m[3], Arch: m[4]}
+
+			archivePath := filepath.Join(typeDir, entry.Name())
+			unpackedDir := filepath.Join(typeDir, version.String(), platform.String())
+			if _, err := os.Stat(unpackedDir); err == nil {
+				// Already converted.
+				continue
+			}
+
+			if err := convertPackedArchive(typeDir, archivePath, unpackedDir); err != nil {
+				return converted, fmt.Errorf("failed to convert %s: %s", archivePath, err)
+			}
+			converted = append(converted, ConvertedPackage{Provider: provider, Version: version, Platform: platform})
+		}
+	}
+	return converted, nil
+}
+
+func convertPackedArchive(typeDir, archivePath, unpackedDir string) error {
+	tmpDir, err := ioutil.TempDir(typeDir, ".convert-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary extraction directory: %s", err)
+	}
+	// If anything below fails before the rename, clean up the scratch
+	// directory rather than leaving it behind for the next scan to trip
+	// over; once the rename succeeds tmpDir no longer exists at this path,
+	// so this is a harmless no-op on the success path.
+	defer os.RemoveAll(tmpDir)
+
+	if err := ExtractPackageArchive(archivePath, tmpDir); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(unpackedDir), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpDir, unpackedDir); err != nil {
+		return fmt.Errorf("failed to move extracted package into place: %s", err)
+	}
+
+	if err := os.Remove(archivePath); err != nil {
+		return fmt.Errorf("failed to remove packed archive after conversion: %s", err)
+	}
+	if err := removeIfExists(archivePath + ".sha256"); err != nil {
+		return err
+	}
+	return nil
+}
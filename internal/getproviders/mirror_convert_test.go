@@ -0,0 +1,60 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConvertPackedToUnpacked(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-convert-mirror")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "null")
+	writeTestPackedArchive(t, typeDir, "terraform-provider-null_1.0.0_linux_amd64.zip", map[string]string{
+		"terraform-provider-null_v1.0.0_x5": "fake binary",
+	})
+	if err := ioutil.WriteFile(filepath.Join(typeDir, "terraform-provider-null_1.0.0_linux_amd64.zip.sha256"), []byte("deadbeef"), 0644); err != nil {
+		t.Fatalf("failed to write sidecar checksum: %s", err)
+	}
+
+	converted, err := ConvertPackedToUnpacked(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(converted) != 1 {
+		t.Fatalf("wrong converted list: got %v", converted)
+	}
+	if got, want := converted[0].Version.String(), "1.0.0"; got != want {
+		t.Errorf("wrong converted version: got %s, want %s", got, want)
+	}
+
+	unpackedFile := filepath.Join(typeDir, "1.0.0", "linux_amd64", "terraform-provider-null_v1.0.0_x5")
+	content, err := ioutil.ReadFile(unpackedFile)
+	if err != nil {
+		t.Fatalf("expected unpacked executable at %s: %s", unpackedFile, err)
+	}
+	if string(content) != "fake binary" {
+		t.Errorf("wrong unpacked content: got %q", content)
+	}
+
+	if _, err := os.Stat(filepath.Join(typeDir, "terraform-provider-null_1.0.0_linux_amd64.zip")); !os.IsNotExist(err) {
+		t.Errorf("expected packed archive to be removed after conversion")
+	}
+	if _, err := os.Stat(filepath.Join(typeDir, "terraform-provider-null_1.0.0_linux_amd64.zip.sha256")); !os.IsNotExist(err) {
+		t.Errorf("expected sidecar checksum to be removed after conversion")
+	}
+
+	// Converting again should be a no-op: nothing packed is left to convert.
+	converted, err = ConvertPackedToUnpacked(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error on second pass: %s", err)
+	}
+	if len(converted) != 0 {
+		t.Errorf("expected nothing left to convert, got %v", converted)
+	}
+}
@@ -0,0 +1,77 @@
+package getproviders
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// MirrorCoverageProblem describes one provider that a MirrorCoveragePolicy
+// required to be available from a mirror, but that the mirror didn't have
+// any versions of.
+type MirrorCoverageProblem struct {
+	Provider addrs.Provider
+}
+
+// MirrorCoveragePolicy enforces that specific providers -- selected by
+// pattern, using the same matching syntax as MultiSource -- must be
+// satisfiable from a mirror alone, without falling back to a provider's
+// origin registry.
+//
+// This is for an air-gapped or registry-restricted environment that wants
+// to fail loudly and early when a required provider was never mirrored,
+// rather than only discovering that when something tries to reach a
+// registry it has no route to.
+type MirrorCoveragePolicy struct {
+	// Required lists the patterns identifying providers that must be
+	// available from Mirror.
+	Required MultiSourceMatchingPatterns
+
+	// Mirror is the source checked for coverage of Required. It should be
+	// the mirror-only selector out of a larger MultiSource, not the
+	// MultiSource itself -- checking the MultiSource would trivially
+	// "succeed" by falling back to the very registry access this policy
+	// exists to rule out.
+	Mirror Source
+}
+
+// Check verifies that every one of providers matching p.Required has at
+// least one version available from p.Mirror, returning one
+// MirrorCoverageProblem for each that doesn't.
+//
+// A non-empty result means the policy was violated; callers should treat
+// that as a hard failure rather than silently falling back, and can use the
+// result to tell an operator exactly which providers still need to be
+// added to the mirror.
+func (p MirrorCoveragePolicy) Check(providers []addrs.Provider) ([]MirrorCoverageProblem, error) {
+	var problems []MirrorCoverageProblem
+	for _, provider := range providers {
+		if !p.Required.MatchesProvider(provider) {
+			continue
+		}
+		versions, err := p.Mirror.AvailableVersions(provider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check mirror coverage for %s: %s", provider, err)
+		}
+		if len(versions) == 0 {
+			problems = append(problems, MirrorCoverageProblem{Provider: provider})
+		}
+	}
+	return problems, nil
+}
+
+// FormatMirrorCoverageProblems renders problems as a single multi-line
+// report suitable for showing directly to a user, or the empty string if
+// problems is empty.
+func FormatMirrorCoverageProblems(problems []MirrorCoverageProblem) string {
+	if len(problems) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(problems)+1)
+	lines = append(lines, "the following providers are required to be available from a local mirror, but were not found there:")
+	for _, problem := range problems {
+		lines = append(lines, fmt.Sprintf("  - %s", problem.Provider))
+	}
+	return strings.Join(lines, "\n")
+}
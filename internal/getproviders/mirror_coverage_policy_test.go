@@ -0,0 +1,60 @@
+package getproviders
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestMirrorCoveragePolicyCheck(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	mirror := NewFilesystemMirrorSource(baseDir)
+	covered := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+	uncovered := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type1")
+
+	patterns, err := ParseMultiSourceMatchingPatterns([]string{"namespace0/*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	policy := MirrorCoveragePolicy{Required: patterns, Mirror: mirror}
+
+	problems, err := policy.Check([]addrs.Provider{covered, uncovered})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(problems) != 1 || problems[0].Provider != uncovered {
+		t.Fatalf("wrong problems: got %#v, want exactly one problem for %s", problems, uncovered)
+	}
+
+	report := FormatMirrorCoverageProblems(problems)
+	if report == "" {
+		t.Errorf("expected a non-empty report")
+	}
+}
+
+func TestMirrorCoveragePolicyCheckNoProblems(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	mirror := NewFilesystemMirrorSource(baseDir)
+	covered := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	patterns, err := ParseMultiSourceMatchingPatterns([]string{"namespace0/*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	policy := MirrorCoveragePolicy{Required: patterns, Mirror: mirror}
+
+	problems, err := policy.Check([]addrs.Provider{covered})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected no problems, got %#v", problems)
+	}
+	if got := FormatMirrorCoverageProblems(problems); got != "" {
+		t.Errorf("expected an empty report, got %q", got)
+	}
+}
@@ -0,0 +1,186 @@
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generationsCurrentFilename is the name of the small text file directly
+// inside a GenerationalMirror's base directory that names which
+// generation subdirectory is currently live.
+const generationsCurrentFilename = "CURRENT"
+
+// generationsSubdir is the name of the directory inside a
+// GenerationalMirror's base directory that holds one immutable
+// subdirectory per generation, named after its generation number.
+const generationsSubdir = "generations"
+
+// GenerationalMirror lays a generation/indirection scheme over a plain
+// filesystem mirror directory, so that a sync process writing a new set of
+// packages never mutates the directory a concurrent reader already
+// resolved. Each sync writes a brand new, immutable generation directory
+// and only then atomically repoints the CURRENT file at it; a reader that
+// already read the old CURRENT value keeps reading a complete, untouched
+// generation until the next time it asks.
+//
+// This trades disk space -- old generations accumulate until pruned -- for
+// never exposing a half-written package to a concurrent reader, which a
+// scheme that updated one shared mirror directory in place cannot
+// guarantee.
+type GenerationalMirror struct {
+	baseDir string
+}
+
+// NewGenerationalMirror returns a GenerationalMirror rooted at baseDir,
+// which it creates on first use if it doesn't already exist.
+func NewGenerationalMirror(baseDir string) *GenerationalMirror {
+	return &GenerationalMirror{baseDir: baseDir}
+}
+
+// CurrentGeneration returns the generation number currently published by
+// CURRENT, or 0 if no generation has ever been published yet.
+func (m *GenerationalMirror) CurrentGeneration() (int, error) {
+	data, err := ioutil.ReadFile(filepath.Join(m.baseDir, generationsCurrentFilename))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %s", generationsCurrentFilename, err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", generationsCurrentFilename, err)
+	}
+	return n, nil
+}
+
+// ContentDir returns the content directory of the currently-published
+// generation, suitable for passing to NewFilesystemMirrorSource. It
+// returns an error if no generation has been published yet.
+func (m *GenerationalMirror) ContentDir() (string, error) {
+	gen, err := m.CurrentGeneration()
+	if err != nil {
+		return "", err
+	}
+	if gen == 0 {
+		return "", fmt.Errorf("no generation has been published in %s yet", m.baseDir)
+	}
+	return m.generationDir(gen), nil
+}
+
+// Source returns a FilesystemMirrorSource reading the currently-published
+// generation. Since a FilesystemMirrorSource caches its directory scan,
+// callers that hold one across the commit of a new generation (see
+// BeginGeneration) should discard it (or
+// call Refresh on a new one from ContentDir) to see the newly-published
+// generation.
+func (m *GenerationalMirror) Source() (*FilesystemMirrorSource, error) {
+	dir, err := m.ContentDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewFilesystemMirrorSource(dir), nil
+}
+
+func (m *GenerationalMirror) generationDir(gen int) string {
+	return filepath.Join(m.baseDir, generationsSubdir, strconv.Itoa(gen))
+}
+
+// BeginGeneration creates a new, empty generation directory, one past
+// whatever is currently published, and returns it for the caller to
+// populate -- typically by running a full mirror sync into it, such as via
+// MirrorSyncDaemon's Fetch callback or ExtractMirrorSubset -- before
+// calling the returned commit function.
+//
+// The new directory is not visible to readers until commit is called, so
+// a sync that fails partway through can simply be abandoned (optionally
+// followed by os.RemoveAll on dir) without ever affecting CURRENT.
+func (m *GenerationalMirror) BeginGeneration() (dir string, commit func() error, err error) {
+	current, err := m.CurrentGeneration()
+	if err != nil {
+		return "", nil, err
+	}
+	next := current + 1
+
+	dir = m.generationDir(next)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create generation directory %s: %s", dir, err)
+	}
+
+	commit = func() error {
+		return m.publish(next)
+	}
+	return dir, commit, nil
+}
+
+// publish atomically repoints CURRENT at gen by writing the new value to a
+// temporary file in the same directory and renaming it over CURRENT, which
+// is atomic on every filesystem Terraform supports as long as both paths
+// are on the same volume.
+func (m *GenerationalMirror) publish(gen int) error {
+	tmp, err := ioutil.TempFile(m.baseDir, generationsCurrentFilename+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary %s file: %s", generationsCurrentFilename, err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := fmt.Fprintf(tmp, "%d\n", gen); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temporary %s file: %s", generationsCurrentFilename, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temporary %s file: %s", generationsCurrentFilename, err)
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(m.baseDir, generationsCurrentFilename)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to publish generation %d: %s", gen, err)
+	}
+	return nil
+}
+
+// PruneOldGenerations deletes every generation directory except the
+// currently-published generation and the keep most recent others. The
+// current generation is always preserved and is not counted against keep.
+func (m *GenerationalMirror) PruneOldGenerations(keep int) error {
+	current, err := m.CurrentGeneration()
+	if err != nil {
+		return err
+	}
+
+	entries, err := readDirIfExists(filepath.Join(m.baseDir, generationsSubdir))
+	if err != nil {
+		return fmt.Errorf("failed to read generations directory: %s", err)
+	}
+
+	var gens []int
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if n, err := strconv.Atoi(entry.Name()); err == nil {
+			gens = append(gens, n)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(gens)))
+
+	kept := 0
+	for _, gen := range gens {
+		if gen == current || kept < keep {
+			if gen != current {
+				kept++
+			}
+			continue
+		}
+		if err := os.RemoveAll(m.generationDir(gen)); err != nil {
+			return fmt.Errorf("failed to remove old generation %d: %s", gen, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerationalMirrorPublish(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-generational-mirror")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	m := NewGenerationalMirror(baseDir)
+
+	if _, err := m.ContentDir(); err == nil {
+		t.Fatalf("expected error reading ContentDir before any generation is published")
+	}
+
+	dir1, commit1, err := m.BeginGeneration()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir1, "marker"), []byte("one"), 0644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := commit1(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	contentDir, err := m.ContentDir()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if contentDir != dir1 {
+		t.Fatalf("ContentDir returned %s, want %s", contentDir, dir1)
+	}
+
+	dir2, commit2, err := m.BeginGeneration()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if dir2 == dir1 {
+		t.Fatalf("second generation reused the first generation's directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir1, "marker")); err != nil {
+		t.Fatalf("first generation's contents were disturbed: %s", err)
+	}
+	if err := commit2(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	contentDir, err = m.ContentDir()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if contentDir != dir2 {
+		t.Fatalf("ContentDir returned %s after second publish, want %s", contentDir, dir2)
+	}
+}
+
+func TestGenerationalMirrorPrune(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-generational-mirror")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	m := NewGenerationalMirror(baseDir)
+
+	var dirs []string
+	for i := 0; i < 4; i++ {
+		dir, commit, err := m.BeginGeneration()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if err := commit(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	if err := m.PruneOldGenerations(1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := os.Stat(dirs[3]); err != nil {
+		t.Errorf("current generation was pruned: %s", err)
+	}
+	if _, err := os.Stat(dirs[2]); err != nil {
+		t.Errorf("kept generation was pruned: %s", err)
+	}
+	if _, err := os.Stat(dirs[1]); !os.IsNotExist(err) {
+		t.Errorf("old generation was not pruned")
+	}
+	if _, err := os.Stat(dirs[0]); !os.IsNotExist(err) {
+		t.Errorf("old generation was not pruned")
+	}
+}
@@ -0,0 +1,40 @@
+package getproviders
+
+import "path/filepath"
+
+// DefaultMirrorIgnorePatterns is the default set of filepath.Match glob
+// patterns that directory scanning filters out before interpreting entries
+// as part of a mirror's hostname/namespace/type/version/os_arch layout.
+//
+// It covers version control metadata and common editor/OS clutter that
+// sometimes ends up inside a mirror directory -- .git, .DS_Store, editor
+// backup files -- but was never meant to be part of that layout, so that
+// scanning doesn't waste time descending into it or, in
+// AllAvailablePackagesStrict's case, report it as a problem.
+var DefaultMirrorIgnorePatterns = []string{
+	".*",        // dotfiles and dot-directories, including .git and .DS_Store
+	"*~",        // editor backup files
+	"*.swp",     // vim swap files
+	"*.swo",
+	"Thumbs.db", // Windows Explorer thumbnail cache
+}
+
+// MirrorIgnorePatterns is the active set of glob patterns that directory
+// scanning filters out, initialized to DefaultMirrorIgnorePatterns.
+//
+// This is a package-level setting, rather than a parameter threaded through
+// every scan function, so that every scan path -- including ones reached
+// indirectly through EnumerableSource -- is covered uniformly. Set it to
+// nil to disable filtering entirely.
+var MirrorIgnorePatterns = DefaultMirrorIgnorePatterns
+
+// isMirrorIgnored returns true if name matches any of MirrorIgnorePatterns,
+// and so should be excluded from a mirror directory listing.
+func isMirrorIgnored(name string) bool {
+	for _, pattern := range MirrorIgnorePatterns {
+		if matched, err := filepath.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
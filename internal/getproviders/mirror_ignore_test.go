@@ -0,0 +1,57 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsMirrorIgnored(t *testing.T) {
+	tests := map[string]bool{
+		".git":       true,
+		".DS_Store":  true,
+		"foo~":       true,
+		"foo.swp":    true,
+		"foo.swo":    true,
+		"Thumbs.db":  true,
+		"namespace0": false,
+		"1.0.0":      false,
+		"linux_amd64": false,
+	}
+	for name, want := range tests {
+		if got := isMirrorIgnored(name); got != want {
+			t.Errorf("isMirrorIgnored(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestReadDirIfExistsSkipsIgnoredEntries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-getproviders-ignore")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create fixture: %s", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "namespace0"), 0755); err != nil {
+		t.Fatalf("failed to create fixture: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ".DS_Store"), nil, 0644); err != nil {
+		t.Fatalf("failed to create fixture: %s", err)
+	}
+
+	entries, err := readDirIfExists(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "namespace0" {
+		names := make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = e.Name()
+		}
+		t.Fatalf("wrong entries: got %v, want [namespace0]", names)
+	}
+}
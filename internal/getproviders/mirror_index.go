@@ -0,0 +1,152 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mirrorIndexFilename is the optional per-provider index document a
+// filesystem mirror can carry, in the same shape the HTTP provider mirror
+// protocol serves at .../<namespace>/<type>/index.json, listing every
+// version available without the source needing to walk the directory
+// itself.
+const mirrorIndexFilename = "index.json"
+
+// mirrorIndexDocument is the JSON shape of mirrorIndexFilename.
+type mirrorIndexDocument struct {
+	Versions map[string]struct{} `json:"versions"`
+}
+
+// mirrorVersionDocument is the JSON shape of the optional "<version>.json"
+// document a filesystem mirror can carry alongside mirrorIndexFilename,
+// mirroring the per-version document the HTTP provider mirror protocol
+// serves at .../<namespace>/<type>/<version>.json.
+type mirrorVersionDocument struct {
+	Archives map[string]mirrorArchive `json:"archives"`
+}
+
+// mirrorArchive is a single platform's entry in a mirrorVersionDocument.
+type mirrorArchive struct {
+	URL    string   `json:"url"`
+	Hashes []string `json:"hashes"`
+}
+
+// readMirrorIndex reads and parses mirrorIndexFilename from typeDir, if
+// present. It returns ok false, with no error, if the file doesn't exist,
+// so that a caller can fall back to walking typeDir directly the way a
+// mirror populated before this convention existed requires.
+func readMirrorIndex(typeDir string) (VersionList, bool, error) {
+	path := filepath.Join(typeDir, mirrorIndexFilename)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %s", path, err)
+	}
+
+	var doc mirrorIndexDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("invalid %s: %s", path, err)
+	}
+
+	versions := make(VersionList, 0, len(doc.Versions))
+	for str := range doc.Versions {
+		v, err := ParseVersion(str)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid version %q in %s: %s", str, path, err)
+		}
+		versions = append(versions, v)
+	}
+	versions.Sort()
+	return versions, true, nil
+}
+
+// readMirrorVersionDocument reads and parses the "<version>.json" document
+// for version from typeDir, if present, returning ok false with no error
+// if it doesn't exist.
+func readMirrorVersionDocument(typeDir string, version Version) (*mirrorVersionDocument, bool, error) {
+	path := filepath.Join(typeDir, version.String()+".json")
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %s", path, err)
+	}
+
+	var doc mirrorVersionDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("invalid %s: %s", path, err)
+	}
+	return &doc, true, nil
+}
+
+// packageMetaFromMirrorIndex builds a PackageMeta for target out of the
+// "<version>.json" document for version in typeDir, if both the document
+// and an entry for target exist in it.
+//
+// An archive URL with a scheme, such as "https://...", is taken as a
+// PackageHTTPURL, so that the same index documents WriteMirrorIndex
+// produces can also be served directly as a network mirror; a URL with no
+// scheme is resolved as a path relative to typeDir and taken as a
+// PackageLocalArchive, the common case for a mirror meant only to be read
+// locally by FilesystemMirrorSource.
+func packageMetaFromMirrorIndex(typeDir string, version Version, target Platform) (PackageMeta, bool, error) {
+	doc, ok, err := readMirrorVersionDocument(typeDir, version)
+	if err != nil || !ok {
+		return PackageMeta{}, false, err
+	}
+
+	archive, ok := doc.Archives[target.String()]
+	if !ok {
+		return PackageMeta{}, false, nil
+	}
+
+	meta := PackageMeta{
+		TargetPlatform: target,
+		Filename:       filepath.Base(archive.URL),
+	}
+	if hasURLScheme(archive.URL) {
+		meta.Location = PackageHTTPURL(archive.URL)
+	} else {
+		meta.Location = PackageLocalArchive(filepath.Join(typeDir, filepath.FromSlash(archive.URL)))
+	}
+
+	for _, hash := range archive.Hashes {
+		if sum, ok := parseHashSchemeZip(hash); ok {
+			meta.SHA256Sum = sum
+			continue
+		}
+		meta.Hashes = append(meta.Hashes, hash)
+	}
+
+	return meta, true, nil
+}
+
+func hasURLScheme(s string) bool {
+	i := strings.Index(s, "://")
+	return i > 0
+}
+
+// parseHashSchemeZip extracts the SHA256 sum from a HashSchemeZip ("zh:")
+// hash string, returning ok false if hash doesn't use that scheme or isn't
+// a valid hex-encoded SHA256 sum.
+func parseHashSchemeZip(hash string) (sum [sha256.Size]byte, ok bool) {
+	rest := strings.TrimPrefix(hash, string(HashSchemeZip))
+	if rest == hash {
+		return sum, false
+	}
+	decoded, err := hex.DecodeString(rest)
+	if err != nil || len(decoded) != len(sum) {
+		return sum, false
+	}
+	copy(sum[:], decoded)
+	return sum, true
+}
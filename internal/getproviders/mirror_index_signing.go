@@ -0,0 +1,92 @@
+package getproviders
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+// mirrorIndexSignatureSuffix is appended to an index document's filename to
+// name its detached, ASCII-armored OpenPGP signature -- "index.json.sig"
+// alongside "index.json", "1.2.3.json.sig" alongside "1.2.3.json".
+const mirrorIndexSignatureSuffix = ".sig"
+
+// SignMirrorIndex signs every index.json and "<version>.json" document
+// WriteMirrorIndex previously wrote under baseDir with signer, writing a
+// detached, ASCII-armored signature alongside each as
+// "<document>.sig".
+//
+// This is meant to be run by the same operator process that calls
+// WriteMirrorIndex, after it, so that a mirror served from a static bucket
+// or other storage an operator doesn't fully trust can still be verified
+// by a Source before it trusts the index contents -- protecting against a
+// compromised or misconfigured bucket silently serving tampered version or
+// checksum information. It doesn't re-sign or touch the package archives
+// themselves, only the index documents describing them.
+func SignMirrorIndex(baseDir string, signer *openpgp.Entity) error {
+	packages, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		return err
+	}
+
+	for provider, versions := range packages {
+		typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+
+		if err := signMirrorIndexDocument(typeDir, mirrorIndexFilename, signer); err != nil {
+			return err
+		}
+		for _, version := range versions {
+			filename := version.String() + ".json"
+			if err := signMirrorIndexDocument(typeDir, filename, signer); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func signMirrorIndexDocument(typeDir, filename string, signer *openpgp.Entity) error {
+	path := filepath.Join(typeDir, filename)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s to sign it: %s", path, err)
+	}
+
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, signer, bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("failed to sign %s: %s", path, err)
+	}
+
+	sigPath := path + mirrorIndexSignatureSuffix
+	if err := ioutil.WriteFile(sigPath, sig.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %s", sigPath, err)
+	}
+	return nil
+}
+
+// VerifyMirrorIndexSignature checks document's detached, ASCII-armored
+// signature against keyRing, the counterpart check to the one
+// SignMirrorIndex's output is meant for. It returns the signing identity's
+// description on success, or an error explaining why the signature didn't
+// verify.
+//
+// A Source reading index documents served from storage it doesn't fully
+// trust -- HTTPMirrorSource's eventual implementation, in particular, for
+// a mirror hosted in a static bucket -- should call this on every
+// index.json and "<version>.json" it downloads before trusting its
+// contents, the same way PackageAuthentication does for a provider
+// publisher's checksums document.
+func VerifyMirrorIndexSignature(document, signature []byte, keyRing openpgp.EntityList) (string, error) {
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyRing, bytes.NewReader(document), bytes.NewReader(signature))
+	if err != nil {
+		return "", fmt.Errorf("signature verification failed: %s", err)
+	}
+
+	for _, identity := range signer.Identities {
+		return fmt.Sprintf("signed by %s", identity.Name), nil
+	}
+	return "signature verified", nil
+}
@@ -0,0 +1,70 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+func TestSignAndVerifyMirrorIndex(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	if err := WriteMirrorIndex(baseDir); err != nil {
+		t.Fatalf("unexpected error writing mirror index: %s", err)
+	}
+
+	signer, err := openpgp.NewEntity("Test Signer", "", "signer@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %s", err)
+	}
+
+	if err := SignMirrorIndex(baseDir, signer); err != nil {
+		t.Fatalf("unexpected error signing mirror index: %s", err)
+	}
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "namespace0", "type0")
+	indexPath := filepath.Join(typeDir, mirrorIndexFilename)
+	document, err := ioutil.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("failed to read %s: %s", indexPath, err)
+	}
+	signature, err := ioutil.ReadFile(indexPath + mirrorIndexSignatureSuffix)
+	if err != nil {
+		t.Fatalf("failed to read signature: %s", err)
+	}
+
+	keyRing := openpgp.EntityList{signer}
+	if _, err := VerifyMirrorIndexSignature(document, signature, keyRing); err != nil {
+		t.Fatalf("unexpected error verifying signature: %s", err)
+	}
+
+	if _, err := VerifyMirrorIndexSignature(append(document, 'x'), signature, keyRing); err == nil {
+		t.Fatalf("expected error verifying signature against tampered document")
+	}
+}
+
+func TestSignMirrorIndexSignsVersionDocuments(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 1)
+	defer close()
+
+	if err := WriteMirrorIndex(baseDir); err != nil {
+		t.Fatalf("unexpected error writing mirror index: %s", err)
+	}
+
+	signer, err := openpgp.NewEntity("Test Signer", "", "signer@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %s", err)
+	}
+	if err := SignMirrorIndex(baseDir, signer); err != nil {
+		t.Fatalf("unexpected error signing mirror index: %s", err)
+	}
+
+	sigPath := filepath.Join(baseDir, "registry.terraform.io", "namespace0", "type0", "0.0.0.json"+mirrorIndexSignatureSuffix)
+	if _, err := os.Stat(sigPath); err != nil {
+		t.Fatalf("expected version document signature at %s: %s", sigPath, err)
+	}
+}
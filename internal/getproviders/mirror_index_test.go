@@ -0,0 +1,66 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestFilesystemMirrorSourceWithIndex(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-mirror-index")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+	if err := os.MkdirAll(typeDir, 0755); err != nil {
+		t.Fatalf("failed to create type dir: %s", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(typeDir, mirrorIndexFilename), []byte(`{"versions": {"3.1.0": {}}}`), 0644); err != nil {
+		t.Fatalf("failed to write index.json: %s", err)
+	}
+
+	packageData := []byte("fake package contents")
+	sum := sha256.Sum256(packageData)
+	if err := ioutil.WriteFile(filepath.Join(typeDir, "terraform-provider-null_3.1.0_linux_amd64.zip"), packageData, 0644); err != nil {
+		t.Fatalf("failed to write fake package: %s", err)
+	}
+
+	versionDoc := `{"archives": {"linux_amd64": {"url": "terraform-provider-null_3.1.0_linux_amd64.zip", "hashes": ["zh:` + hex.EncodeToString(sum[:]) + `"]}}}`
+	if err := ioutil.WriteFile(filepath.Join(typeDir, "3.1.0.json"), []byte(versionDoc), 0644); err != nil {
+		t.Fatalf("failed to write version document: %s", err)
+	}
+
+	source := NewFilesystemMirrorSource(baseDir)
+
+	versions, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(versions) != 1 || versions[0].String() != "3.1.0" {
+		t.Fatalf("wrong versions: got %v", versions)
+	}
+
+	meta, err := source.PackageMeta(provider, versions[0], Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if meta.SHA256Sum != sum {
+		t.Errorf("wrong SHA256Sum: got %x, want %x", meta.SHA256Sum, sum)
+	}
+	archive, ok := meta.Location.(PackageLocalArchive)
+	if !ok {
+		t.Fatalf("wrong location type %T", meta.Location)
+	}
+	if string(archive) != filepath.Join(typeDir, "terraform-provider-null_3.1.0_linux_amd64.zip") {
+		t.Errorf("wrong archive path: got %s", archive)
+	}
+}
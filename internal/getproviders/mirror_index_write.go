@@ -0,0 +1,115 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// WriteMirrorIndex scans the filesystem mirror at baseDir and writes an
+// index.json and one "<version>.json" document per version into each
+// provider's type directory, in the same shape readMirrorIndex and
+// readMirrorVersionDocument expect, so the directory can afterwards either
+// be read through FilesystemMirrorSource's fast index path instead of a
+// full walk, or served directly over HTTP as a network mirror.
+//
+// It's the write-side counterpart of the read support added for index.json
+// and is meant to be run by a mirror operator -- by hand, or as a step of
+// whatever process populates the mirror -- rather than by Terraform itself
+// during normal operation.
+//
+// Only packed (zip archive) packages are represented in the generated
+// version documents, since the archives they describe are what the HTTP
+// provider mirror protocol downloads; an unpacked-only package version is
+// still listed in index.json, but with no entries in its version document,
+// since there's no single file to point a URL at.
+//
+// WriteMirrorIndex only ever adds or overwrites index documents; it never
+// touches the package files themselves, so it's safe to re-run after
+// adding new packages to pick them up.
+func WriteMirrorIndex(baseDir string) error {
+	packages, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		return err
+	}
+
+	for provider := range packages {
+		typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+
+		// The scan above answers from any index.json already in typeDir, so
+		// the versions to index are read from the directory itself, following
+		// symlinked entries.
+		versions, err := scanMirrorVersionsFollowingSymlinks(typeDir, defaultSymlinkFollowDepth)
+		if err != nil {
+			return err
+		}
+
+		if err := writeMirrorIndexDocument(typeDir, versions); err != nil {
+			return err
+		}
+		for _, version := range versions {
+			if err := writeMirrorVersionDocument(typeDir, version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeMirrorIndexDocument(typeDir string, versions VersionList) error {
+	doc := mirrorIndexDocument{Versions: make(map[string]struct{}, len(versions))}
+	for _, v := range versions {
+		doc.Versions[v.String()] = struct{}{}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s for %s: %s", mirrorIndexFilename, typeDir, err)
+	}
+	path := filepath.Join(typeDir, mirrorIndexFilename)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %s", path, err)
+	}
+	return nil
+}
+
+// writeMirrorVersionDocument writes typeDir/<version>.json, with one
+// archive entry per platform a packed zip exists for at that version.
+func writeMirrorVersionDocument(typeDir string, version Version) error {
+	entries, err := readDirIfExists(typeDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+
+	doc := mirrorVersionDocument{Archives: make(map[string]mirrorArchive)}
+	for _, entry := range entries {
+		m := packageFilenamePattern.FindStringSubmatch(entry.Name())
+		if m == nil || m[2] != version.String() {
+			continue
+		}
+		platform := m[3] + "_" + m[4]
+
+		data, err := ioutil.ReadFile(filepath.Join(typeDir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %s", entry.Name(), err)
+		}
+		sum := sha256.Sum256(data)
+
+		doc.Archives[platform] = mirrorArchive{
+			URL:    entry.Name(),
+			Hashes: []string{fmt.Sprintf("%s%x", HashSchemeZip, sum)},
+		}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode version document for %s: %s", version, err)
+	}
+	path := filepath.Join(typeDir, version.String()+".json")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %s", path, err)
+	}
+	return nil
+}
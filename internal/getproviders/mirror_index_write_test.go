@@ -0,0 +1,37 @@
+package getproviders
+
+import (
+	"os"
+	"testing"
+)
+
+func TestWriteMirrorIndexRoundTrip(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	if err := WriteMirrorIndex(baseDir); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	source := NewFilesystemMirrorSource(baseDir)
+	available, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for provider, versions := range available {
+		got, err := source.AvailableVersions(provider)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(got) != len(versions) {
+			t.Errorf("wrong number of versions for %s after WriteMirrorIndex: got %d, want %d", provider, len(got), len(versions))
+		}
+	}
+}
+
+func TestWriteMirrorIndexMissingDir(t *testing.T) {
+	if err := WriteMirrorIndex(os.TempDir() + "/terraform-mirror-index-does-not-exist"); err != nil {
+		t.Fatalf("unexpected error for a nonexistent mirror: %s", err)
+	}
+}
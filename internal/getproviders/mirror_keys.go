@@ -0,0 +1,45 @@
+package getproviders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// MirrorKeysDir is the conventional name of the directory, alongside a
+// mirror's usual hostname/namespace/type/version/os_arch layout, where
+// publisher public keys are kept so that signature verification still
+// works with no access to the registry that originally vouched for them.
+const MirrorKeysDir = "keys"
+
+// LoadMirrorSigningKeys reads every ASCII-armored public key file under
+// <mirrorDir>/keys/<namespace>/<type>/*.asc, returning their contents as
+// the SigningKeys a PackageMeta for that provider should carry.
+//
+// A mirror populates this directory by exporting each provider's publisher
+// key from the registry (or its own key, for packages it publishes
+// itself) when it's built, so that PackageMeta.Authentication can still
+// reach the strongest verification level even when the installer has no
+// internet access at all.
+func LoadMirrorSigningKeys(mirrorDir string, provider addrs.Provider) ([]string, error) {
+	keysDir := filepath.Join(mirrorDir, MirrorKeysDir, provider.Namespace, provider.Type)
+	entries, err := readDirIfExists(keysDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", keysDir, err)
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".asc" {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(keysDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", entry.Name(), err)
+		}
+		keys = append(keys, string(data))
+	}
+	return keys, nil
+}
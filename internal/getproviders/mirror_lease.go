@@ -0,0 +1,192 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// LeaseDatabase tracks, for a single shared package cache directory, which
+// working directories currently depend on which provider package versions
+// in that cache.
+//
+// A cache shared by many working directories -- a plugin cache dir mounted
+// read-write across a fleet of build farm workers, in particular -- can't
+// safely be pruned using only KeepFromLockFiles, because a workspace that
+// last ran hours or days ago might still be holding a working directory
+// open with no lock file change due since: its lease here is what tells
+// Prune not to delete the package out from under it. The database itself
+// is just a JSON file at Path, read and rewritten in full on every call, so
+// it's only suitable for the low write volume a cache's occasional init/
+// cleanup operations produce, not for high-frequency concurrent access.
+type LeaseDatabase struct {
+	// Path is the JSON file used to persist the lease database. It's
+	// created on first use if it doesn't already exist.
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewLeaseDatabase constructs a LeaseDatabase backed by the given path.
+func NewLeaseDatabase(path string) *LeaseDatabase {
+	return &LeaseDatabase{Path: path}
+}
+
+// leaseDatabaseDocument is the on-disk JSON representation of a
+// LeaseDatabase. addrs.Provider has no JSON encoding of its own, so each
+// lease's provider is broken out into its three parts instead.
+type leaseDatabaseDocument struct {
+	Leases []leaseRecord `json:"leases"`
+}
+
+type leaseRecord struct {
+	WorkingDir string `json:"working_dir"`
+	Hostname   string `json:"hostname"`
+	Namespace  string `json:"namespace"`
+	Type       string `json:"type"`
+	Version    string `json:"version"`
+}
+
+// Record registers workingDir as depending on the given provider package
+// version, creating the lease database file if it doesn't yet exist. It's
+// idempotent: recording the same working directory and package again has
+// no additional effect.
+//
+// Callers typically call this once per provider selected during an init,
+// mirroring what they'd also be writing to that working directory's own
+// lock file.
+func (d *LeaseDatabase) Record(workingDir string, provider addrs.Provider, version Version) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	doc, err := d.read()
+	if err != nil {
+		return err
+	}
+
+	for _, lease := range doc.Leases {
+		if lease.WorkingDir == workingDir && lease.Hostname == string(provider.Hostname) && lease.Namespace == provider.Namespace && lease.Type == provider.Type && lease.Version == version.String() {
+			return nil
+		}
+	}
+
+	doc.Leases = append(doc.Leases, leaseRecord{
+		WorkingDir: workingDir,
+		Hostname:   string(provider.Hostname),
+		Namespace:  provider.Namespace,
+		Type:       provider.Type,
+		Version:    version.String(),
+	})
+	return d.write(doc)
+}
+
+// Release removes every lease recorded for workingDir, such as when a
+// workspace is destroyed or its .terraform directory is otherwise known to
+// no longer exist.
+func (d *LeaseDatabase) Release(workingDir string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	doc, err := d.read()
+	if err != nil {
+		return err
+	}
+
+	kept := doc.Leases[:0]
+	for _, lease := range doc.Leases {
+		if lease.WorkingDir == workingDir {
+			continue
+		}
+		kept = append(kept, lease)
+	}
+	doc.Leases = kept
+	return d.write(doc)
+}
+
+// leased returns every provider/version pair currently leased by at least
+// one working directory, as a set keyed by mirrorPruneKey.
+func (d *LeaseDatabase) leased() (map[string]bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	doc, err := d.read()
+	if err != nil {
+		return nil, err
+	}
+
+	leased := make(map[string]bool, len(doc.Leases))
+	for _, lease := range doc.Leases {
+		provider := addrs.NewProvider(svchost.Hostname(lease.Hostname), lease.Namespace, lease.Type)
+		version, err := ParseVersion(lease.Version)
+		if err != nil {
+			// Not expected in practice, since only Record writes these
+			// entries, but we'd rather skip a corrupt entry than fail
+			// every caller that asks what's leased.
+			continue
+		}
+		leased[mirrorPruneKey(provider, version)] = true
+	}
+	return leased, nil
+}
+
+func (d *LeaseDatabase) read() (leaseDatabaseDocument, error) {
+	var doc leaseDatabaseDocument
+
+	data, err := ioutil.ReadFile(d.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return doc, nil
+		}
+		return doc, fmt.Errorf("failed to read lease database %s: %s", d.Path, err)
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return doc, fmt.Errorf("failed to parse lease database %s: %s", d.Path, err)
+	}
+	return doc, nil
+}
+
+func (d *LeaseDatabase) write(doc leaseDatabaseDocument) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize lease database: %s", err)
+	}
+	if err := ioutil.WriteFile(d.Path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lease database %s: %s", d.Path, err)
+	}
+	return nil
+}
+
+// KeepLeased returns a keep function for Prune that retains every provider
+// package version d has a current lease for, regardless of whether any
+// lock file still mentions it.
+//
+// This is meant to be combined with KeepFromLockFiles or
+// KeepWithinSizeBudget rather than used alone, since d only knows about
+// working directories that have actually called Record: a caller usually
+// wants to prune to whichever of those two policies it already uses, but
+// never prune out from under a working directory that's actively leasing a
+// package, so a typical keep function is:
+//
+//	leased, err := leaseDB.KeepLeased()
+//	...
+//	budget, err := KeepWithinSizeBudget(baseDir, maxBytes)
+//	...
+//	keep := func(p addrs.Provider, v Version) bool {
+//		return leased(p, v) || budget(p, v)
+//	}
+func (d *LeaseDatabase) KeepLeased() (func(addrs.Provider, Version) bool, error) {
+	leased, err := d.leased()
+	if err != nil {
+		return nil, err
+	}
+	return func(provider addrs.Provider, version Version) bool {
+		return leased[mirrorPruneKey(provider, version)]
+	}, nil
+}
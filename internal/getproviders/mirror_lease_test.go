@@ -0,0 +1,86 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestLeaseDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-lease-db")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db := NewLeaseDatabase(filepath.Join(dir, "leases.json"))
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+
+	if err := db.Record("/work/a", provider, versionMust(t, "1.0.0")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// Recording the same lease again should be a harmless no-op.
+	if err := db.Record("/work/a", provider, versionMust(t, "1.0.0")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	keep, err := db.KeepLeased()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !keep(provider, versionMust(t, "1.0.0")) {
+		t.Errorf("expected leased version to be kept")
+	}
+	if keep(provider, versionMust(t, "2.0.0")) {
+		t.Errorf("expected unleased version not to be kept")
+	}
+
+	if err := db.Release("/work/a"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	keep, err = db.KeepLeased()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keep(provider, versionMust(t, "1.0.0")) {
+		t.Errorf("expected version to no longer be kept after Release")
+	}
+}
+
+func TestLeaseDatabasePrune(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	dir, err := ioutil.TempDir("", "terraform-lease-db")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db := NewLeaseDatabase(filepath.Join(dir, "leases.json"))
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	if err := db.Record("/work/a", provider, versionMust(t, "0.0.0")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	leased, err := db.KeepLeased()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	keep := func(p addrs.Provider, v Version) bool {
+		return leased(p, v)
+	}
+
+	pruned, err := Prune(baseDir, keep)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(pruned) != 1 || pruned[0].Version.String() != "1.0.0" {
+		t.Fatalf("wrong pruned list: got %v", pruned)
+	}
+}
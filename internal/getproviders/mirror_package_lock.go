@@ -0,0 +1,72 @@
+package getproviders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// packageLockStaleAfter is how long an existing per-package lock file is
+// trusted before it's assumed to have been left behind by a process that
+// crashed or was killed mid-install, and is safe to steal.
+//
+// This is shorter than cacheLockStaleAfter because a single package
+// install is expected to finish in seconds to low minutes, not the hour or
+// more a whole shared cache's worth of work might reasonably take.
+const packageLockStaleAfter = 10 * time.Minute
+
+// PackageLock represents a held advisory lock on a single provider package
+// version's corner of a shared mirror or cache directory, obtained by
+// AcquirePackageLock.
+type PackageLock struct {
+	path string
+}
+
+// AcquirePackageLock obtains an advisory lock scoped to one provider
+// package version within baseDir, so that concurrent Terraform processes
+// populating the same shared mirror or plugin cache directory don't race
+// to write the same version's zip or unpacked directory and corrupt one
+// another's downloads -- while still letting them install unrelated
+// providers, or other versions of the same provider, fully in parallel,
+// unlike the whole-directory AcquireCacheLock.
+//
+// It retries acquiring the lock until it succeeds or until timeout
+// elapses, at which point it returns an error. A lock file older than
+// packageLockStaleAfter is treated as abandoned by a process that didn't
+// shut down cleanly and is stolen rather than waited on.
+//
+// The caller must call Release on the returned PackageLock once it's done
+// writing the package's files.
+func AcquirePackageLock(baseDir string, provider addrs.Provider, version Version, timeout time.Duration) (*PackageLock, error) {
+	typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+	if err := os.MkdirAll(typeDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %s", typeDir, err)
+	}
+	path := filepath.Join(typeDir, "."+version.String()+".lock")
+
+	if err := acquireAdvisoryLockFile(path, packageLockStaleAfter, timeout); err != nil {
+		return nil, err
+	}
+	return &PackageLock{path: path}, nil
+}
+
+// Release removes the lock file, allowing another process waiting in
+// AcquirePackageLock for the same provider version to proceed.
+func (l *PackageLock) Release() error {
+	return os.Remove(l.path)
+}
+
+// WithPackageLock acquires a lock on the given provider version within
+// baseDir, as AcquirePackageLock does, runs f, and releases the lock
+// before returning, regardless of whether f succeeded.
+func WithPackageLock(baseDir string, provider addrs.Provider, version Version, timeout time.Duration, f func() error) error {
+	lock, err := AcquirePackageLock(baseDir, provider, version, timeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	return f()
+}
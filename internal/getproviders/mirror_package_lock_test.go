@@ -0,0 +1,118 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestAcquirePackageLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-package-lock")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	providerA := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	providerB := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "random")
+
+	lock, err := AcquirePackageLock(dir, providerA, versionMust(t, "1.0.0"), time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	typeDir := filepath.Join(dir, "registry.terraform.io", "hashicorp", "null")
+	if _, err := os.Stat(filepath.Join(typeDir, ".1.0.0.lock")); err != nil {
+		t.Fatalf("lock file was not created: %s", err)
+	}
+
+	if _, err := AcquirePackageLock(dir, providerA, versionMust(t, "1.0.0"), 200*time.Millisecond); err == nil {
+		t.Fatalf("expected second acquire of the same provider version to time out")
+	}
+
+	// A different version of the same provider, and a different provider
+	// entirely, must both be free to lock concurrently.
+	otherVersionLock, err := AcquirePackageLock(dir, providerA, versionMust(t, "2.0.0"), time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error locking a different version: %s", err)
+	}
+	defer otherVersionLock.Release()
+
+	otherProviderLock, err := AcquirePackageLock(dir, providerB, versionMust(t, "1.0.0"), time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error locking a different provider: %s", err)
+	}
+	defer otherProviderLock.Release()
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("unexpected error releasing lock: %s", err)
+	}
+
+	lock2, err := AcquirePackageLock(dir, providerA, versionMust(t, "1.0.0"), time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error re-acquiring released lock: %s", err)
+	}
+	lock2.Release()
+}
+
+func TestAcquirePackageLockStale(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-package-lock")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	typeDir := filepath.Join(dir, "registry.terraform.io", "hashicorp", "null")
+	if err := os.MkdirAll(typeDir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %s", typeDir, err)
+	}
+	path := filepath.Join(typeDir, ".1.0.0.lock")
+	if err := ioutil.WriteFile(path, []byte("12345\n"), 0644); err != nil {
+		t.Fatalf("failed to write stale lock file: %s", err)
+	}
+	staleTime := time.Now().Add(-2 * packageLockStaleAfter)
+	if err := os.Chtimes(path, staleTime, staleTime); err != nil {
+		t.Fatalf("failed to backdate lock file: %s", err)
+	}
+
+	lock, err := AcquirePackageLock(dir, provider, versionMust(t, "1.0.0"), time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error stealing stale lock: %s", err)
+	}
+	lock.Release()
+}
+
+func TestWithPackageLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-package-lock")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	path := filepath.Join(dir, "registry.terraform.io", "hashicorp", "null", ".1.0.0.lock")
+
+	var ran bool
+	err = WithPackageLock(dir, provider, versionMust(t, "1.0.0"), time.Second, func() error {
+		ran = true
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("lock file missing while f is running: %s", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ran {
+		t.Fatalf("f was not called")
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("lock file still present after WithPackageLock returned")
+	}
+}
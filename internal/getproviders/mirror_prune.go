@@ -0,0 +1,214 @@
+package getproviders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// PrunedPackage describes one provider package version Prune removed from
+// a filesystem mirror.
+type PrunedPackage struct {
+	Provider addrs.Provider
+	Version  Version
+}
+
+// Prune deletes every provider package version in the filesystem mirror at
+// baseDir for which keep returns false -- its unpacked directory, its
+// packed zip archive, and the sidecar checksum and index documents that
+// describe just that version -- so that a long-lived shared mirror or
+// plugin cache dir doesn't grow without bound as old versions accumulate.
+//
+// keep is typically built from KeepFromLockFiles, to retain only what's
+// still referenced by a fleet of lock files, or from KeepWithinSizeBudget,
+// to retain the most recently used versions up to a total size budget; a
+// caller with its own retention policy can also implement keep directly.
+//
+// It never removes a provider's type directory itself, or anything that
+// doesn't look like a recognized package file or directory for one of its
+// versions, even if keep would prune every version found -- Prune only
+// ever removes what AllAvailablePackages would have reported.
+func Prune(baseDir string, keep func(addrs.Provider, Version) bool) ([]PrunedPackage, error) {
+	packages, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []PrunedPackage
+	for provider, versions := range packages {
+		typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+		for _, version := range versions {
+			if keep(provider, version) {
+				continue
+			}
+			if err := prunePackageVersion(typeDir, version); err != nil {
+				return pruned, err
+			}
+			pruned = append(pruned, PrunedPackage{Provider: provider, Version: version})
+		}
+	}
+	return pruned, nil
+}
+
+func prunePackageVersion(typeDir string, version Version) error {
+	unpackedDir := filepath.Join(typeDir, version.String())
+	if info, err := os.Stat(unpackedDir); err == nil && info.IsDir() {
+		if err := os.RemoveAll(unpackedDir); err != nil {
+			return fmt.Errorf("failed to remove %s: %s", unpackedDir, err)
+		}
+	}
+
+	entries, err := readDirIfExists(typeDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+	for _, entry := range entries {
+		m := packageFilenamePattern.FindStringSubmatch(entry.Name())
+		if m == nil || m[2] != version.String() {
+			continue
+		}
+		path := filepath.Join(typeDir, entry.Name())
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %s", path, err)
+		}
+		if err := removeIfExists(path + ".sha256"); err != nil {
+			return err
+		}
+	}
+
+	versionDoc := filepath.Join(typeDir, version.String()+".json")
+	if err := removeIfExists(versionDoc); err != nil {
+		return err
+	}
+	if err := removeIfExists(versionDoc + mirrorIndexSignatureSuffix); err != nil {
+		return err
+	}
+	return nil
+}
+
+func removeIfExists(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %s", path, err)
+	}
+	return nil
+}
+
+// KeepFromLockFiles returns a keep function for Prune that retains exactly
+// the provider/version pairs recorded across lockFiles, the common case of
+// pruning a shared mirror down to only what a fleet of workspaces' lock
+// files still reference.
+func KeepFromLockFiles(lockFiles ...LockFile) func(addrs.Provider, Version) bool {
+	keep := make(map[string]bool)
+	for _, lockFile := range lockFiles {
+		for provider, locked := range lockFile.Providers {
+			keep[mirrorPruneKey(provider, locked.Version)] = true
+		}
+	}
+	return func(provider addrs.Provider, version Version) bool {
+		return keep[mirrorPruneKey(provider, version)]
+	}
+}
+
+// KeepWithinSizeBudget scans the filesystem mirror at baseDir and returns a
+// keep function for Prune that retains the most recently used versions --
+// by the modification time of their package files, the closest proxy for
+// "last used" available without a separate access-time database -- up to a
+// total on-disk size of maxBytes, pruning the least recently used versions
+// first once that budget would otherwise be exceeded.
+func KeepWithinSizeBudget(baseDir string, maxBytes int64) (func(addrs.Provider, Version) bool, error) {
+	packages, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	type usage struct {
+		provider addrs.Provider
+		version  Version
+		size     int64
+		usedAt   time.Time
+	}
+	var all []usage
+	for provider, versions := range packages {
+		typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+		for _, version := range versions {
+			size, usedAt, err := packageVersionSizeAndUsage(typeDir, version)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, usage{provider, version, size, usedAt})
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].usedAt.After(all[j].usedAt)
+	})
+
+	keep := make(map[string]bool, len(all))
+	var total int64
+	for _, u := range all {
+		total += u.size
+		if total > maxBytes {
+			break
+		}
+		keep[mirrorPruneKey(u.provider, u.version)] = true
+	}
+
+	return func(provider addrs.Provider, version Version) bool {
+		return keep[mirrorPruneKey(provider, version)]
+	}, nil
+}
+
+// packageVersionSizeAndUsage returns the total size on disk of version's
+// package files within typeDir -- its unpacked directory, its packed zip,
+// or both -- and the most recent modification time among them, used as a
+// last-used proxy.
+func packageVersionSizeAndUsage(typeDir string, version Version) (int64, time.Time, error) {
+	var size int64
+	var usedAt time.Time
+	note := func(info os.FileInfo) {
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		if info.ModTime().After(usedAt) {
+			usedAt = info.ModTime()
+		}
+	}
+
+	unpackedDir := filepath.Join(typeDir, version.String())
+	err := filepath.Walk(unpackedDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		note(info)
+		return nil
+	})
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to stat %s: %s", unpackedDir, err)
+	}
+
+	entries, err := readDirIfExists(typeDir)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+	for _, entry := range entries {
+		m := packageFilenamePattern.FindStringSubmatch(entry.Name())
+		if m == nil || m[2] != version.String() {
+			continue
+		}
+		note(entry)
+	}
+
+	return size, usedAt, nil
+}
+
+func mirrorPruneKey(provider addrs.Provider, version Version) string {
+	return provider.String() + "@" + version.String()
+}
@@ -0,0 +1,97 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestPrune(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+	keep := func(p addrs.Provider, v Version) bool {
+		return v.String() == "1.0.0"
+	}
+
+	pruned, err := Prune(baseDir, keep)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(pruned) != 1 || pruned[0].Version.String() != "0.0.0" {
+		t.Fatalf("wrong pruned list: got %v", pruned)
+	}
+
+	remaining, err := AvailablePackagesFor(baseDir, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(remaining) != 1 || remaining[0].String() != "1.0.0" {
+		t.Fatalf("wrong remaining versions: got %v", remaining)
+	}
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "namespace0", "type0")
+	if _, err := os.Stat(filepath.Join(typeDir, "0.0.0")); !os.IsNotExist(err) {
+		t.Errorf("expected pruned version directory to be removed")
+	}
+}
+
+func TestKeepFromLockFiles(t *testing.T) {
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	lockFile := LockFile{
+		Providers: map[addrs.Provider]LockedProvider{
+			provider: {Version: versionMust(t, "1.0.0")},
+		},
+	}
+
+	keep := KeepFromLockFiles(lockFile)
+	if !keep(provider, versionMust(t, "1.0.0")) {
+		t.Errorf("expected the locked version to be kept")
+	}
+	if keep(provider, versionMust(t, "2.0.0")) {
+		t.Errorf("expected an unlocked version not to be kept")
+	}
+}
+
+func TestKeepWithinSizeBudget(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "namespace0", "type0")
+	old := time.Now().Add(-time.Hour)
+	recent := time.Now()
+
+	oldFile := filepath.Join(typeDir, "0.0.0", "linux_amd64", "terraform-provider-type0")
+	if err := ioutil.WriteFile(oldFile, []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("failed to write fixture file: %s", err)
+	}
+	if err := os.Chtimes(oldFile, old, old); err != nil {
+		t.Fatalf("failed to set mtime: %s", err)
+	}
+
+	newFile := filepath.Join(typeDir, "1.0.0", "linux_amd64", "terraform-provider-type0")
+	if err := ioutil.WriteFile(newFile, []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("failed to write fixture file: %s", err)
+	}
+	if err := os.Chtimes(newFile, recent, recent); err != nil {
+		t.Fatalf("failed to set mtime: %s", err)
+	}
+
+	keep, err := KeepWithinSizeBudget(baseDir, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+	if !keep(provider, versionMust(t, "1.0.0")) {
+		t.Errorf("expected the more recently used version to be kept")
+	}
+	if keep(provider, versionMust(t, "0.0.0")) {
+		t.Errorf("expected the less recently used version to be pruned")
+	}
+}
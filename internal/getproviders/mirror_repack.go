@@ -0,0 +1,88 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// repackEntryModTime is the fixed modification time RepackUnpacked stamps
+// onto every entry it writes, rather than preserving whatever mtimes the
+// unpacked files happen to have on the machine doing the repacking. It's
+// the oldest date the zip format itself can represent, chosen only for
+// that reason, not for any calendar significance.
+var repackEntryModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// RepackUnpacked creates a zip archive at zipPath containing the contents
+// of the already-unpacked provider package found in dir, the inverse of
+// ExtractPackageArchive.
+//
+// The result is byte-for-byte reproducible given the same input files: its
+// entries are written in the same sorted order PackageDirHash uses, and
+// every entry's recorded modification time and permissions are fixed
+// rather than copied from the filesystem producing them. This means two
+// machines that unpacked the same original package -- or rebuilt it from
+// the same source -- and then repacked it will produce identical zip
+// files, so the resulting archive's HashSchemeZip hash can be recorded in
+// a lock file and verified by anyone regardless of where it was built.
+//
+// Permissions are reduced to the same two classes ExtractPackageArchive
+// normalizes to on the way in: an entry whose owner could execute it is
+// written back out as executable, and everything else is written back out
+// as a plain file, so repacking an archive that was itself produced by
+// ExtractPackageArchive round-trips exactly.
+func RepackUnpacked(dir, zipPath string) error {
+	paths, err := sortedPackageDirFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %s", zipPath, err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, rel := range paths {
+		if err := addRepackEntry(zw, dir, rel); err != nil {
+			return fmt.Errorf("failed to add %s to %s: %s", rel, zipPath, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish writing %s: %s", zipPath, err)
+	}
+	return nil
+}
+
+func addRepackEntry(zw *zip.Writer, dir, rel string) error {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	header := &zip.FileHeader{
+		Name:     rel,
+		Method:   zip.Deflate,
+		Modified: repackEntryModTime,
+	}
+	header.SetMode(normalizedFileMode(info.Mode()))
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(w, src)
+	return err
+}
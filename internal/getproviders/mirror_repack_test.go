@@ -0,0 +1,92 @@
+package getproviders
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepackUnpackedDeterministic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-repack-unpacked")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "terraform-provider-null_v1.0.0_x5"), []byte("fake binary"), 0755); err != nil {
+		t.Fatalf("failed to write fixture file: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "LICENSE.txt"), []byte("license text"), 0644); err != nil {
+		t.Fatalf("failed to write fixture file: %s", err)
+	}
+
+	zipPathA := filepath.Join(os.TempDir(), "terraform-repack-a.zip")
+	zipPathB := filepath.Join(os.TempDir(), "terraform-repack-b.zip")
+	defer os.Remove(zipPathA)
+	defer os.Remove(zipPathB)
+
+	if err := RepackUnpacked(dir, zipPathA); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// Touching an mtime between the two repacks must not change the
+	// result: only the file content and sorted entry order should matter.
+	if err := os.Chtimes(filepath.Join(dir, "LICENSE.txt"), repackEntryModTime, repackEntryModTime.AddDate(1, 0, 0)); err != nil {
+		t.Fatalf("failed to change mtime: %s", err)
+	}
+	if err := RepackUnpacked(dir, zipPathB); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	contentA, err := ioutil.ReadFile(zipPathA)
+	if err != nil {
+		t.Fatalf("failed to read %s: %s", zipPathA, err)
+	}
+	contentB, err := ioutil.ReadFile(zipPathB)
+	if err != nil {
+		t.Fatalf("failed to read %s: %s", zipPathB, err)
+	}
+	if !bytes.Equal(contentA, contentB) {
+		t.Errorf("repacking the same directory twice produced different archives")
+	}
+}
+
+func TestRepackUnpackedRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-repack-unpacked")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "terraform-provider-null_v1.0.0_x5"), []byte("fake binary"), 0755); err != nil {
+		t.Fatalf("failed to write fixture file: %s", err)
+	}
+
+	zipPath := filepath.Join(os.TempDir(), "terraform-repack-roundtrip.zip")
+	defer os.Remove(zipPath)
+	if err := RepackUnpacked(dir, zipPath); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	destDir, err := ioutil.TempDir("", "terraform-repack-extracted")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(destDir)
+	if err := ExtractPackageArchive(zipPath, destDir); err != nil {
+		t.Fatalf("unexpected error extracting repacked archive: %s", err)
+	}
+
+	originalHash, err := PackageDirHash(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	roundTrippedHash, err := PackageDirHash(destDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if originalHash != roundTrippedHash {
+		t.Errorf("round-tripped package has a different hash: got %s, want %s", roundTrippedHash, originalHash)
+	}
+}
@@ -0,0 +1,150 @@
+package getproviders
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// mirrorScanJob is one provider type directory discoverMirrorScanJobs found
+// under a mirror's baseDir, still needing scanMirrorVersions run against it.
+type mirrorScanJob struct {
+	provider addrs.Provider
+	typeDir  string
+}
+
+// AllAvailablePackagesConcurrent is a drop-in replacement for
+// AllAvailablePackages that scans a mirror's provider type directories
+// across a worker pool instead of one at a time.
+//
+// AllAvailablePackages is serial because a typical local mirror has few
+// enough providers that the walk finishes before it would matter; on a
+// mirror served from a network filesystem, where most of the scan's wall
+// time is round-trip latency rather than CPU work, that serial walk can
+// dominate a cold "terraform init". concurrency controls how many type
+// directories are scanned in parallel; a value of zero or less uses
+// runtime.GOMAXPROCS(0).
+//
+// The result is identical to what AllAvailablePackages would return for the
+// same baseDir -- only the order the filesystem is visited in, not the
+// answer, differs -- so callers can switch between the two based purely on
+// where baseDir lives.
+func AllAvailablePackagesConcurrent(baseDir string, concurrency int) (map[addrs.Provider]VersionList, error) {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	jobs, err := discoverMirrorScanJobs(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret map[addrs.Provider]VersionList
+	pprof.Do(context.Background(), pprof.Labels("getproviders.scan_phase", "scan_mirror_concurrent"), func(context.Context) {
+		ret, err = runMirrorScanJobs(jobs, concurrency)
+	})
+	return ret, err
+}
+
+// discoverMirrorScanJobs walks baseDir's hostname/namespace/type layout,
+// the same way scanMirrorPackages does, but stops short of reading each
+// type directory's versions, since that's the part AllAvailablePackagesConcurrent
+// wants to parallelize.
+func discoverMirrorScanJobs(baseDir string) ([]mirrorScanJob, error) {
+	var jobs []mirrorScanJob
+
+	hostEntries, err := readDirIfExists(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", baseDir, err)
+	}
+	for _, hostEntry := range hostEntries {
+		if !hostEntry.IsDir() {
+			continue
+		}
+		hostname, err := svchost.ForComparison(hostEntry.Name())
+		if err != nil {
+			continue // not a valid hostname directory
+		}
+		hostDir := filepath.Join(baseDir, hostEntry.Name())
+
+		namespaceEntries, err := readDirIfExists(hostDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", hostDir, err)
+		}
+		for _, namespaceEntry := range namespaceEntries {
+			if !namespaceEntry.IsDir() {
+				continue
+			}
+			namespaceDir := filepath.Join(hostDir, namespaceEntry.Name())
+
+			typeEntries, err := readDirIfExists(namespaceDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", namespaceDir, err)
+			}
+			for _, typeEntry := range typeEntries {
+				if !typeEntry.IsDir() {
+					continue
+				}
+				jobs = append(jobs, mirrorScanJob{
+					provider: addrs.NewProvider(hostname, namespaceEntry.Name(), typeEntry.Name()),
+					typeDir:  filepath.Join(namespaceDir, typeEntry.Name()),
+				})
+			}
+		}
+	}
+
+	return jobs, nil
+}
+
+// runMirrorScanJobs runs scanMirrorVersions for each job across concurrency
+// goroutines, merging the results into a single map once every job has
+// finished. The first error encountered, if any, wins; the rest are
+// discarded, matching the serial scan's behavior of stopping at the first
+// problem it finds.
+func runMirrorScanJobs(jobs []mirrorScanJob, concurrency int) (map[addrs.Provider]VersionList, error) {
+	queue := make(chan mirrorScanJob)
+	go func() {
+		defer close(queue)
+		for _, job := range jobs {
+			queue <- job
+		}
+	}()
+
+	ret := make(map[addrs.Provider]VersionList)
+	var mu sync.Mutex
+	var firstErr error
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				versions, err := scanMirrorVersions(job.typeDir)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					addMirrorScanResult(ret, job.provider, versions)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return ret, nil
+}
@@ -0,0 +1,73 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestAllAvailablePackagesConcurrent(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 2, 2, 3)
+	defer close()
+
+	want, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, concurrency := range []int{0, 1, 4} {
+		got, err := AllAvailablePackagesConcurrent(baseDir, concurrency)
+		if err != nil {
+			t.Fatalf("unexpected error with concurrency %d: %s", concurrency, err)
+		}
+		if len(got) != len(want) {
+			t.Errorf("concurrency %d: wrong number of providers: got %d, want %d", concurrency, len(got), len(want))
+		}
+		for provider, versions := range want {
+			if !reflect.DeepEqual(got[provider], versions) {
+				t.Errorf("concurrency %d: wrong versions for %s: got %v, want %v", concurrency, provider, got[provider], versions)
+			}
+		}
+	}
+}
+
+func TestAllAvailablePackagesConcurrentMergesCaseVariants(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-getproviders-casefold")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "HashiCorp", "aws", "1.0.0", "linux_amd64"))
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "aws", "2.0.0", "linux_amd64"))
+
+	got, err := AllAvailablePackagesConcurrent(baseDir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "aws")
+	versions, ok := got[provider]
+	if !ok {
+		t.Fatalf("no entry for %s in %#v", provider, got)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("wrong number of merged versions: got %d, want 2: %#v", len(versions), versions)
+	}
+}
+
+func BenchmarkAllAvailablePackagesConcurrent(b *testing.B) {
+	baseDir, close := testSyntheticMirror(b, 20, 20, 10)
+	defer close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := AllAvailablePackagesConcurrent(baseDir, 0); err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
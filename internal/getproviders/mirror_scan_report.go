@@ -0,0 +1,99 @@
+package getproviders
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MirrorScanReport summarizes the MirrorScanProblem values found by a
+// strict mirror scan (see AllAvailablePackagesStrict) into per-category
+// counts, so tooling can show something like "mirror has 14 unusable
+// entries" instead of requiring a user to read each path-by-path problem
+// individually or dig through TRACE logs.
+type MirrorScanReport struct {
+	Problems []MirrorScanProblem
+}
+
+// NewMirrorScanReport wraps problems, such as those returned by
+// AllAvailablePackagesStrict, in a MirrorScanReport.
+func NewMirrorScanReport(problems []MirrorScanProblem) MirrorScanReport {
+	return MirrorScanReport{Problems: problems}
+}
+
+// Count returns the total number of skipped entries the scan found.
+func (r MirrorScanReport) Count() int {
+	return len(r.Problems)
+}
+
+// MirrorScanReasonCount is one entry of MirrorScanReport.ReasonCounts: how
+// many problems fell into a given category.
+type MirrorScanReasonCount struct {
+	Category string
+	Count    int
+}
+
+// ReasonCounts groups r's problems into broad categories -- invalid
+// hostname, invalid version, invalid platform, wrong depth, or
+// unrecognized filename -- ordered by descending count and then
+// alphabetically by category, to keep output deterministic.
+func (r MirrorScanReport) ReasonCounts() []MirrorScanReasonCount {
+	counts := make(map[string]int)
+	for _, p := range r.Problems {
+		counts[categorizeMirrorScanProblem(p)]++
+	}
+
+	result := make([]MirrorScanReasonCount, 0, len(counts))
+	for category, count := range counts {
+		result = append(result, MirrorScanReasonCount{Category: category, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Category < result[j].Category
+	})
+	return result
+}
+
+// Summary renders a one-line, human-readable digest of the report, such as
+// "mirror has 14 unusable entries (9 invalid version, 4 invalid platform, 1
+// invalid hostname)", or an empty string if the report has no problems.
+func (r MirrorScanReport) Summary() string {
+	if len(r.Problems) == 0 {
+		return ""
+	}
+
+	counts := r.ReasonCounts()
+	parts := make([]string, len(counts))
+	for i, c := range counts {
+		parts[i] = fmt.Sprintf("%d %s", c.Count, c.Category)
+	}
+
+	noun := "entry"
+	if len(r.Problems) != 1 {
+		noun = "entries"
+	}
+	return fmt.Sprintf("mirror has %d unusable %s (%s)", len(r.Problems), noun, strings.Join(parts, ", "))
+}
+
+// categorizeMirrorScanProblem classifies a MirrorScanProblem's free-form
+// Reason text, as produced by scanMirrorPackagesStrict and
+// scanMirrorVersionsStrict, into one of a small set of stable category
+// names.
+func categorizeMirrorScanProblem(p MirrorScanProblem) string {
+	switch {
+	case strings.Contains(p.Reason, "does not match the expected"):
+		return "unrecognized filename"
+	case strings.Contains(p.Reason, "hostname"):
+		return "invalid hostname"
+	case strings.Contains(p.Reason, "version"):
+		return "invalid version"
+	case strings.Contains(p.Reason, "platform"):
+		return "invalid platform"
+	case strings.Contains(p.Reason, "expected a"):
+		return "wrong depth"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,38 @@
+package getproviders
+
+import "testing"
+
+func TestMirrorScanReport(t *testing.T) {
+	report := NewMirrorScanReport([]MirrorScanProblem{
+		{Path: "/mirror/bad-host", Reason: "not a valid hostname: invalid label"},
+		{Path: "/mirror/example.com/ns/type/not-a-version", Reason: "not a valid version directory: invalid version string"},
+		{Path: "/mirror/example.com/ns/type/1.0.0/not-a-platform", Reason: "not a valid platform directory: must be two words separated by an underscore"},
+		{Path: "/mirror/example.com/ns/type/1.0.0/not-a-platform-2", Reason: "not a valid platform directory: must be two words separated by an underscore"},
+		{Path: "/mirror/example.com/ns", Reason: "expected a namespace directory, found a file"},
+	})
+
+	if report.Count() != 5 {
+		t.Fatalf("wrong count: got %d, want 5", report.Count())
+	}
+
+	counts := report.ReasonCounts()
+	if len(counts) != 4 {
+		t.Fatalf("wrong number of categories: got %d: %v", len(counts), counts)
+	}
+	if counts[0].Category != "invalid platform" || counts[0].Count != 2 {
+		t.Errorf("wrong top category: got %+v", counts[0])
+	}
+
+	summary := report.Summary()
+	want := "mirror has 5 unusable entries (2 invalid platform, 1 invalid hostname, 1 invalid version, 1 wrong depth)"
+	if summary != want {
+		t.Errorf("wrong summary:\ngot:  %s\nwant: %s", summary, want)
+	}
+}
+
+func TestMirrorScanReportEmpty(t *testing.T) {
+	report := NewMirrorScanReport(nil)
+	if report.Summary() != "" {
+		t.Errorf("expected an empty summary for a clean scan, got %q", report.Summary())
+	}
+}
@@ -0,0 +1,29 @@
+package getproviders
+
+import (
+	"context"
+	"path/filepath"
+	"runtime/pprof"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// AvailablePackagesFor behaves like AllAvailablePackages, except that it
+// only scans provider's own hostname/namespace/type subdirectory within
+// baseDir, rather than walking the whole mirror.
+//
+// This is what the auto-install discovery path usually wants: it already
+// knows which provider it's resolving and has no use for the rest of the
+// mirror's contents, so there's no reason to pay for a full scan just to
+// throw most of it away. AllAvailablePackages remains the right choice for
+// a caller that does need the whole mirror's contents, such as a mirror
+// sync or a "what changed since yesterday" report.
+func AvailablePackagesFor(baseDir string, provider addrs.Provider) (VersionList, error) {
+	var ret VersionList
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("getproviders.scan_phase", "scan_mirror_scoped"), func(context.Context) {
+		typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+		ret, err = scanMirrorVersions(typeDir)
+	})
+	return ret, err
+}
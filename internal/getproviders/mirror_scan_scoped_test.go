@@ -0,0 +1,35 @@
+package getproviders
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestAvailablePackagesFor(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 2, 2, 2)
+	defer close()
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type1")
+	versions, err := AvailablePackagesFor(baseDir, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(versions) != 2 || versions[0].String() != "0.0.0" || versions[1].String() != "1.0.0" {
+		t.Fatalf("wrong versions: got %v", versions)
+	}
+}
+
+func TestAvailablePackagesForUnknownProvider(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 1)
+	defer close()
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "nonexistent", "nonexistent")
+	versions, err := AvailablePackagesFor(baseDir, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(versions) != 0 {
+		t.Errorf("expected no versions, got %v", versions)
+	}
+}
@@ -0,0 +1,220 @@
+package getproviders
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// defaultSymlinkFollowDepth bounds how many symlink hops
+// resolveSymlinkFollowing will follow resolving a single directory entry,
+// used whenever AllAvailablePackagesFollowingSymlinks is given a maxDepth
+// of zero or less.
+const defaultSymlinkFollowDepth = 10
+
+// AllAvailablePackagesFollowingSymlinks behaves like AllAvailablePackages,
+// except that it also descends into symlinked directories within baseDir's
+// hostname/namespace/type layout.
+//
+// The plain directory walk AllAvailablePackages uses is built on
+// ioutil.ReadDir, which reports a symlinked version or namespace directory
+// as a non-directory file rather than following it, making mirrors
+// assembled by symlinking a version directory shared across several
+// workspaces into place -- rather than copying it -- invisible. This
+// variant exists for that case, at the cost of needing maxDepth and cycle
+// detection to bound how far it's willing to follow a chain of symlinks
+// before giving up.
+//
+// maxDepth bounds how many symlinks resolveSymlinkFollowing will follow
+// resolving any single directory entry; a value of zero or less uses
+// defaultSymlinkFollowDepth.
+func AllAvailablePackagesFollowingSymlinks(baseDir string, maxDepth int) (map[addrs.Provider]VersionList, error) {
+	if maxDepth <= 0 {
+		maxDepth = defaultSymlinkFollowDepth
+	}
+
+	var ret map[addrs.Provider]VersionList
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("getproviders.scan_phase", "scan_mirror_symlinks"), func(context.Context) {
+		ret, err = scanMirrorPackagesFollowingSymlinks(baseDir, maxDepth)
+	})
+	return ret, err
+}
+
+func scanMirrorPackagesFollowingSymlinks(baseDir string, maxDepth int) (map[addrs.Provider]VersionList, error) {
+	ret := make(map[addrs.Provider]VersionList)
+
+	hostEntries, err := readDirFollowingSymlinks(baseDir, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", baseDir, err)
+	}
+	for _, hostEntry := range hostEntries {
+		if !hostEntry.IsDir() {
+			continue
+		}
+		hostname, err := svchost.ForComparison(hostEntry.Name())
+		if err != nil {
+			continue // not a valid hostname directory
+		}
+		hostDir := filepath.Join(baseDir, hostEntry.Name())
+
+		namespaceEntries, err := readDirFollowingSymlinks(hostDir, maxDepth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", hostDir, err)
+		}
+		for _, namespaceEntry := range namespaceEntries {
+			if !namespaceEntry.IsDir() {
+				continue
+			}
+			namespaceDir := filepath.Join(hostDir, namespaceEntry.Name())
+
+			typeEntries, err := readDirFollowingSymlinks(namespaceDir, maxDepth)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", namespaceDir, err)
+			}
+			for _, typeEntry := range typeEntries {
+				if !typeEntry.IsDir() {
+					continue
+				}
+				provider := addrs.NewProvider(hostname, namespaceEntry.Name(), typeEntry.Name())
+				typeDir := filepath.Join(namespaceDir, typeEntry.Name())
+
+				versions, err := scanMirrorVersionsFollowingSymlinks(typeDir, maxDepth)
+				if err != nil {
+					return nil, err
+				}
+				addMirrorScanResult(ret, provider, versions)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// scanMirrorVersionsFollowingSymlinks is the symlink-following counterpart
+// to scanMirrorVersions, recognizing the same unpacked and packed layouts
+// but reading typeDir with readDirFollowingSymlinks instead of
+// readDirIfExists.
+func scanMirrorVersionsFollowingSymlinks(typeDir string, maxDepth int) (VersionList, error) {
+	entries, err := readDirFollowingSymlinks(typeDir, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+
+	seen := make(map[string]bool)
+	var versions VersionList
+	addIfNew := func(version Version) {
+		key := version.String()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		versions = append(versions, version)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if version, err := ParseVersion(entry.Name()); err == nil {
+				addIfNew(version)
+			}
+			continue
+		}
+
+		if m := packageFilenamePattern.FindStringSubmatch(entry.Name()); m != nil {
+			if version, err := ParseVersion(m[2]); err == nil {
+				addIfNew(version)
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+// readDirFollowingSymlinks behaves like readDirIfExists, except that a
+// symlinked entry -- which ioutil.ReadDir reports as a non-directory file,
+// since it doesn't follow links -- is resolved with resolveSymlinkFollowing
+// first, so that a symlink to a directory is correctly reported as one. An
+// entry whose symlink can't be resolved (it's broken, too deep, or part of
+// a cycle) is silently omitted, consistent with readDirIfExists treating an
+// unreadable entry as simply not there.
+func readDirFollowingSymlinks(dir string, maxDepth int) ([]os.FileInfo, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resolved := make([]os.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if isMirrorIgnored(entry.Name()) {
+			continue
+		}
+		if entry.Mode()&os.ModeSymlink == 0 {
+			resolved = append(resolved, entry)
+			continue
+		}
+
+		info, err := resolveSymlinkFollowing(filepath.Join(dir, entry.Name()), maxDepth)
+		if err != nil {
+			continue
+		}
+		resolved = append(resolved, symlinkResolvedFileInfo{FileInfo: info, name: entry.Name()})
+	}
+	return resolved, nil
+}
+
+// resolveSymlinkFollowing follows the chain of symlinks starting at path,
+// up to maxDepth hops, returning the FileInfo of whatever it eventually
+// resolves to. It returns an error if the chain exceeds maxDepth or visits
+// the same target twice, the latter indicating a symlink loop.
+func resolveSymlinkFollowing(path string, maxDepth int) (os.FileInfo, error) {
+	visited := make(map[string]bool)
+	current := path
+
+	for i := 0; i <= maxDepth; i++ {
+		info, err := os.Lstat(current)
+		if err != nil {
+			return nil, err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return info, nil
+		}
+
+		target, err := os.Readlink(current)
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(current), target)
+		}
+		target = filepath.Clean(target)
+
+		if visited[target] {
+			return nil, fmt.Errorf("symlink loop detected resolving %s", path)
+		}
+		visited[target] = true
+		current = target
+	}
+
+	return nil, fmt.Errorf("exceeded maximum symlink depth (%d) resolving %s", maxDepth, path)
+}
+
+// symlinkResolvedFileInfo reports the Mode/IsDir/Size/etc. of whatever a
+// symlink resolved to, while keeping the symlink's own name, so that a
+// resolved entry still appears under the name it was found as in its
+// parent directory.
+type symlinkResolvedFileInfo struct {
+	os.FileInfo
+	name string
+}
+
+func (fi symlinkResolvedFileInfo) Name() string { return fi.name }
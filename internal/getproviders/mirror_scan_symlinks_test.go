@@ -0,0 +1,101 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestAllAvailablePackagesFollowingSymlinks(t *testing.T) {
+	sharedDir, err := ioutil.TempDir("", "terraform-symlink-shared")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(sharedDir)
+	if err := os.MkdirAll(filepath.Join(sharedDir, "linux_amd64"), 0755); err != nil {
+		t.Fatalf("failed to create shared version dir: %s", err)
+	}
+
+	baseDir, err := ioutil.TempDir("", "terraform-symlink-mirror")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "null")
+	if err := os.MkdirAll(typeDir, 0755); err != nil {
+		t.Fatalf("failed to create type dir: %s", err)
+	}
+	if err := os.Symlink(sharedDir, filepath.Join(typeDir, "3.1.0")); err != nil {
+		t.Skipf("symlinks not supported in this environment: %s", err)
+	}
+
+	// The plain scan can't see the symlinked version directory.
+	plain, err := AllAvailablePackages(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(plain) != 0 {
+		t.Errorf("expected the plain scan to find nothing, got %v", plain)
+	}
+
+	got, err := AllAvailablePackagesFollowingSymlinks(baseDir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	versions, ok := got[provider]
+	if !ok || len(versions) != 1 || versions[0].String() != "3.1.0" {
+		t.Errorf("wrong versions for %s: got %v", provider, versions)
+	}
+}
+
+func TestAllAvailablePackagesFollowingSymlinksMergesCaseVariants(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-getproviders-casefold")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "HashiCorp", "aws", "1.0.0", "linux_amd64"))
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "aws", "2.0.0", "linux_amd64"))
+
+	got, err := AllAvailablePackagesFollowingSymlinks(baseDir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "aws")
+	versions, ok := got[provider]
+	if !ok {
+		t.Fatalf("no entry for %s in %#v", provider, got)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("wrong number of merged versions: got %d, want 2: %#v", len(versions), versions)
+	}
+}
+
+func TestResolveSymlinkFollowingLoop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-symlink-loop")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	if err := os.Symlink(b, a); err != nil {
+		t.Skipf("symlinks not supported in this environment: %s", err)
+	}
+	if err := os.Symlink(a, b); err != nil {
+		t.Fatalf("failed to create symlink: %s", err)
+	}
+
+	if _, err := resolveSymlinkFollowing(a, defaultSymlinkFollowDepth); err == nil {
+		t.Fatalf("expected an error resolving a symlink loop")
+	}
+}
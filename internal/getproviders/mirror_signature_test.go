@@ -0,0 +1,44 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMirrorPackageSignatureAuthenticationMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-mirror-signature")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, ok, err := MirrorPackageSignatureAuthentication(dir, "package.zip", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ok {
+		t.Fatalf("expected no signature to be found")
+	}
+}
+
+func TestMirrorPackageSignatureAuthenticationSumsWithoutSig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-mirror-signature")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, sha256sumsFilename), []byte("irrelevant"), 0644); err != nil {
+		t.Fatalf("failed to write SHA256SUMS: %s", err)
+	}
+
+	_, ok, err := MirrorPackageSignatureAuthentication(dir, "package.zip", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ok {
+		t.Fatalf("expected no signature to be found without a .sig file")
+	}
+}
@@ -0,0 +1,211 @@
+package getproviders
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime/pprof"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// MirrorScanProblem describes one directory entry a strict mirror scan
+// found that didn't fit the layout AllAvailablePackages expects -- an
+// invalid hostname, version, or platform -- together with where it was
+// found.
+//
+// AllAvailablePackages and AllAvailablePackagesConcurrent silently ignore
+// entries like these, on the assumption that a provider cache directory can
+// accumulate unrelated files and directories over time that shouldn't
+// abort a scan. AllAvailablePackagesStrict exists for the opposite
+// assumption: a CI pipeline that builds a mirror from scratch wants to know
+// immediately that its layout is wrong, rather than discovering it later as
+// a provider mysteriously missing at `terraform init` time.
+type MirrorScanProblem struct {
+	Path   string
+	Reason string
+}
+
+func (p MirrorScanProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Reason)
+}
+
+// AllAvailablePackagesStrict behaves like AllAvailablePackages, except that
+// it also returns a MirrorScanProblem for every directory entry it had to
+// skip because it didn't look like a valid hostname, version, or platform,
+// instead of ignoring them.
+//
+// The returned map is always the same one AllAvailablePackages would
+// return for the same baseDir; problems is nil if the scan found nothing
+// irregular. Neither a non-nil problems nor an error necessarily implies
+// the other: a layout problem is reported without an error, since scanning
+// otherwise completed successfully.
+func AllAvailablePackagesStrict(baseDir string) (map[addrs.Provider]VersionList, []MirrorScanProblem, error) {
+	var ret map[addrs.Provider]VersionList
+	var problems []MirrorScanProblem
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("getproviders.scan_phase", "scan_mirror_strict"), func(context.Context) {
+		ret, problems, err = scanMirrorPackagesStrict(baseDir)
+	})
+	return ret, problems, err
+}
+
+func scanMirrorPackagesStrict(baseDir string) (map[addrs.Provider]VersionList, []MirrorScanProblem, error) {
+	ret := make(map[addrs.Provider]VersionList)
+	var problems []MirrorScanProblem
+
+	hostEntries, err := readDirIfExists(baseDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %s", baseDir, err)
+	}
+	for _, hostEntry := range hostEntries {
+		hostPath := filepath.Join(baseDir, hostEntry.Name())
+		if !hostEntry.IsDir() {
+			problems = append(problems, MirrorScanProblem{Path: hostPath, Reason: "expected a hostname directory, found a file"})
+			continue
+		}
+		hostname, err := svchost.ForComparison(hostEntry.Name())
+		if err != nil {
+			problems = append(problems, MirrorScanProblem{Path: hostPath, Reason: fmt.Sprintf("not a valid hostname: %s", err)})
+			continue
+		}
+
+		namespaceEntries, err := readDirIfExists(hostPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %s", hostPath, err)
+		}
+		for _, namespaceEntry := range namespaceEntries {
+			namespacePath := filepath.Join(hostPath, namespaceEntry.Name())
+			if !namespaceEntry.IsDir() {
+				problems = append(problems, MirrorScanProblem{Path: namespacePath, Reason: "expected a namespace directory, found a file"})
+				continue
+			}
+
+			typeEntries, err := readDirIfExists(namespacePath)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read %s: %s", namespacePath, err)
+			}
+			for _, typeEntry := range typeEntries {
+				typePath := filepath.Join(namespacePath, typeEntry.Name())
+				if !typeEntry.IsDir() {
+					problems = append(problems, MirrorScanProblem{Path: typePath, Reason: "expected a provider type directory, found a file"})
+					continue
+				}
+
+				provider := addrs.NewProvider(hostname, namespaceEntry.Name(), typeEntry.Name())
+				versions, typeProblems, err := scanMirrorVersionsStrict(typePath)
+				if err != nil {
+					return nil, nil, err
+				}
+				problems = append(problems, typeProblems...)
+				addMirrorScanResult(ret, provider, versions)
+			}
+		}
+	}
+
+	return ret, problems, nil
+}
+
+// scanMirrorVersionsStrict behaves like scanMirrorVersions, except that it
+// reports a MirrorScanProblem for every entry of typeDir that isn't a
+// recognized version directory, packed package filename, or one of the
+// optional index documents mirror_index.go reads, instead of silently
+// ignoring it. Within an unpacked version directory, it also validates
+// that every platform subdirectory parses as a Platform.
+func scanMirrorVersionsStrict(typeDir string) (VersionList, []MirrorScanProblem, error) {
+	entries, err := readDirIfExists(typeDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+
+	seen := make(map[string]bool)
+	var versions VersionList
+	var problems []MirrorScanProblem
+	addIfNew := func(version Version) {
+		key := version.String()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		versions = append(versions, version)
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(typeDir, entry.Name())
+
+		if entry.IsDir() {
+			version, err := ParseVersion(entry.Name())
+			if err != nil {
+				problems = append(problems, MirrorScanProblem{Path: entryPath, Reason: fmt.Sprintf("not a valid version directory: %s", err)})
+				continue
+			}
+			addIfNew(version)
+
+			platformProblems, err := scanVersionPlatformsStrict(entryPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			problems = append(problems, platformProblems...)
+			continue
+		}
+
+		if isMirrorIndexEntry(entry.Name()) {
+			continue
+		}
+
+		m := packageFilenamePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			problems = append(problems, MirrorScanProblem{Path: entryPath, Reason: "does not match the expected terraform-provider-<type>_<version>_<os>_<arch>.zip filename"})
+			continue
+		}
+		version, err := ParseVersion(m[2])
+		if err != nil {
+			problems = append(problems, MirrorScanProblem{Path: entryPath, Reason: fmt.Sprintf("not a valid version in filename: %s", err)})
+			continue
+		}
+		if _, err := ParsePlatform(m[3] + "_" + m[4]); err != nil {
+			problems = append(problems, MirrorScanProblem{Path: entryPath, Reason: fmt.Sprintf("not a valid platform in filename: %s", err)})
+			continue
+		}
+		addIfNew(version)
+	}
+
+	return versions, problems, nil
+}
+
+func scanVersionPlatformsStrict(versionDir string) ([]MirrorScanProblem, error) {
+	entries, err := readDirIfExists(versionDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", versionDir, err)
+	}
+
+	var problems []MirrorScanProblem
+	for _, entry := range entries {
+		entryPath := filepath.Join(versionDir, entry.Name())
+		if !entry.IsDir() {
+			problems = append(problems, MirrorScanProblem{Path: entryPath, Reason: "expected a <os>_<arch> platform directory, found a file"})
+			continue
+		}
+		if _, err := ParsePlatform(entry.Name()); err != nil {
+			problems = append(problems, MirrorScanProblem{Path: entryPath, Reason: fmt.Sprintf("not a valid platform directory: %s", err)})
+		}
+	}
+	return problems, nil
+}
+
+// isMirrorIndexEntry recognizes the optional index.json and <version>.json
+// documents mirror_index.go reads, which scanMirrorVersionsStrict
+// shouldn't flag as unrecognized files.
+func isMirrorIndexEntry(name string) bool {
+	if name == mirrorIndexFilename {
+		return true
+	}
+	ext := filepath.Ext(name)
+	if ext != ".json" {
+		return false
+	}
+	_, err := ParseVersion(name[:len(name)-len(ext)])
+	return err == nil
+}
@@ -0,0 +1,79 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestAllAvailablePackagesStrictClean(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	_, problems, err := AllAvailablePackagesStrict(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("unexpected problems in a clean mirror: %v", problems)
+	}
+}
+
+func TestAllAvailablePackagesStrictMalformed(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 1)
+	defer close()
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "namespace0", "type0")
+
+	// An unparseable version directory.
+	if err := os.MkdirAll(filepath.Join(typeDir, "not-a-version"), 0755); err != nil {
+		t.Fatalf("failed to create bad version dir: %s", err)
+	}
+	// An unparseable platform directory under a valid version.
+	if err := os.MkdirAll(filepath.Join(typeDir, "0.0.0", "not-a-platform"), 0755); err != nil {
+		t.Fatalf("failed to create bad platform dir: %s", err)
+	}
+	// A file that doesn't match the expected package filename pattern.
+	if err := ioutil.WriteFile(filepath.Join(typeDir, "README.txt"), []byte("hi"), 0644); err != nil {
+		t.Fatalf("failed to create stray file: %s", err)
+	}
+
+	_, problems, err := AllAvailablePackagesStrict(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(problems) != 3 {
+		t.Fatalf("wrong number of problems: got %d, want 3: %v", len(problems), problems)
+	}
+}
+
+func TestAllAvailablePackagesStrictMergesCaseVariants(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-getproviders-casefold")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "HashiCorp", "aws", "1.0.0", "linux_amd64"))
+	mustMkdirAll(t, filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "aws", "2.0.0", "linux_amd64"))
+
+	got, problems, err := AllAvailablePackagesStrict(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "aws")
+	versions, ok := got[provider]
+	if !ok {
+		t.Fatalf("no entry for %s in %#v", provider, got)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("wrong number of merged versions: got %d, want 2: %#v", len(versions), versions)
+	}
+}
@@ -0,0 +1,158 @@
+package getproviders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// MirrorSubsetRequest describes one provider version a call to
+// ExtractMirrorSubset should carry over into the new, smaller mirror,
+// typically built directly from a LockFile so that the subset contains
+// exactly what a particular configuration was last initialized with.
+type MirrorSubsetRequest struct {
+	Provider addrs.Provider
+	Version  Version
+
+	// Platforms restricts which target platforms are extracted for this
+	// provider version. A nil or empty Platforms extracts every platform
+	// found in the source mirror, which is appropriate when the
+	// destination environment's platform isn't known in advance.
+	Platforms []Platform
+}
+
+// ExtractMirrorSubset copies just the provider versions named in reqs out
+// of the filesystem mirror at srcDir into destDir, preserving whichever of
+// the packed or unpacked layouts each package was already stored in, so
+// that destDir is itself a valid, independent filesystem mirror that a
+// product team can carry into a restricted environment without needing
+// the whole of srcDir.
+//
+// It returns an error, without partially writing destDir, if any requested
+// provider version isn't present in srcDir for at least one platform.
+func ExtractMirrorSubset(srcDir, destDir string, reqs []MirrorSubsetRequest) error {
+	for _, req := range reqs {
+		typeDir := filepath.Join(srcDir, string(req.Provider.Hostname), req.Provider.Namespace, req.Provider.Type)
+		destTypeDir := filepath.Join(destDir, string(req.Provider.Hostname), req.Provider.Namespace, req.Provider.Type)
+
+		platforms, err := req.platformsToExtract(typeDir)
+		if err != nil {
+			return err
+		}
+
+		var copied int
+		for _, platform := range platforms {
+			ok, err := copyUnpackedPackage(typeDir, destTypeDir, req.Version, platform)
+			if err != nil {
+				return err
+			}
+			if ok {
+				copied++
+				continue
+			}
+
+			ok, err = copyPackedPackage(typeDir, destTypeDir, req.Version, platform)
+			if err != nil {
+				return err
+			}
+			if ok {
+				copied++
+			}
+		}
+
+		if copied == 0 {
+			return fmt.Errorf("no package found in %s for %s %s", srcDir, req.Provider, req.Version)
+		}
+	}
+
+	return nil
+}
+
+// platformsToExtract returns req.Platforms verbatim if set, or else every
+// platform scanMirrorVersions can see a package for at req.Version.
+func (req MirrorSubsetRequest) platformsToExtract(typeDir string) ([]Platform, error) {
+	if len(req.Platforms) > 0 {
+		return req.Platforms, nil
+	}
+
+	entries, err := readDirIfExists(typeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+
+	var platforms []Platform
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() == req.Version.String() {
+			versionDir := filepath.Join(typeDir, entry.Name())
+			platformEntries, err := readDirIfExists(versionDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", versionDir, err)
+			}
+			for _, platformEntry := range platformEntries {
+				if !platformEntry.IsDir() {
+					continue
+				}
+				if platform, err := ParsePlatform(platformEntry.Name()); err == nil {
+					platforms = append(platforms, platform)
+				}
+			}
+			continue
+		}
+
+		if m := packageFilenamePattern.FindStringSubmatch(entry.Name()); m != nil && m[2] == req.Version.String() {
+			if platform, err := ParsePlatform(m[3] + "_" + m[4]); err == nil {
+				platforms = append(platforms, platform)
+			}
+		}
+	}
+	return platforms, nil
+}
+
+func copyUnpackedPackage(typeDir, destTypeDir string, version Version, target Platform) (bool, error) {
+	srcDir := filepath.Join(typeDir, version.String(), target.String())
+	info, err := os.Stat(srcDir)
+	if err != nil || !info.IsDir() {
+		return false, nil
+	}
+
+	destDir := filepath.Join(destTypeDir, version.String(), target.String())
+	if err := copyDirContents(srcDir, destDir); err != nil {
+		return false, fmt.Errorf("failed to copy %s: %s", srcDir, err)
+	}
+	return true, nil
+}
+
+func copyPackedPackage(typeDir, destTypeDir string, version Version, target Platform) (bool, error) {
+	filename := packedFilename(filepath.Base(typeDir), version, target)
+	srcPath := filepath.Join(typeDir, filename)
+	info, err := os.Stat(srcPath)
+	if err != nil || info.IsDir() {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(destTypeDir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create %s: %s", destTypeDir, err)
+	}
+	destPath := filepath.Join(destTypeDir, filename)
+	if err := copyFileContents(srcPath, destPath); err != nil {
+		return false, fmt.Errorf("failed to copy %s: %s", srcPath, err)
+	}
+	return true, nil
+}
+
+// MirrorSubsetFromLock builds the MirrorSubsetRequest list ExtractMirrorSubset
+// expects directly from a LockFile, extracting every locked provider at its
+// locked version for the given platforms.
+func MirrorSubsetFromLock(lock *LockFile, platforms []Platform) []MirrorSubsetRequest {
+	reqs := make([]MirrorSubsetRequest, 0, len(lock.Providers))
+	for provider, locked := range lock.Providers {
+		reqs = append(reqs, MirrorSubsetRequest{
+			Provider:  provider,
+			Version:   locked.Version,
+			Platforms: platforms,
+		})
+	}
+	return reqs
+}
@@ -0,0 +1,72 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestExtractMirrorSubset(t *testing.T) {
+	srcDir, closeSrc := testSyntheticMirror(t, 2, 2, 3)
+	defer closeSrc()
+
+	available, err := AllAvailablePackages(srcDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var provider addrs.Provider
+	var version Version
+	for p, versions := range available {
+		provider = p
+		version = versions[0]
+		break
+	}
+
+	destDir, err := ioutil.TempDir("", "terraform-mirror-subset")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(destDir)
+
+	err = ExtractMirrorSubset(srcDir, destDir, []MirrorSubsetRequest{
+		{Provider: provider, Version: version},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := AllAvailablePackages(destDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("wrong number of providers in subset: got %d, want 1", len(got))
+	}
+	if versions, ok := got[provider]; !ok || len(versions) != 1 {
+		t.Fatalf("subset doesn't contain exactly %s %s", provider, version)
+	}
+}
+
+func TestExtractMirrorSubsetMissing(t *testing.T) {
+	srcDir, closeSrc := testSyntheticMirror(t, 1, 1, 1)
+	defer closeSrc()
+
+	destDir, err := ioutil.TempDir("", "terraform-mirror-subset")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(destDir)
+
+	missing := addrs.NewProvider(DefaultRegistryHost, "nonexistent", "nonexistent")
+	version, _ := ParseVersion("1.0.0")
+
+	err = ExtractMirrorSubset(srcDir, filepath.Join(destDir, "out"), []MirrorSubsetRequest{
+		{Provider: missing, Version: version},
+	})
+	if err == nil {
+		t.Fatalf("expected error for missing provider version")
+	}
+}
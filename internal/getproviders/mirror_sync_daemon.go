@@ -0,0 +1,201 @@
+package getproviders
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// MirrorSyncTarget describes one provider that a MirrorSyncDaemon should
+// keep mirrored, along with the constraint a newly-appearing upstream
+// version must satisfy before the daemon will bother fetching it.
+type MirrorSyncTarget struct {
+	Provider    addrs.Provider
+	Constraints VersionConstraints
+}
+
+// MirrorSyncMetrics receives counts of what happened during each sync
+// pass, for callers that want to export them to their own metrics system
+// rather than parsing log output.
+//
+// All methods are called synchronously from the daemon's own goroutine, so
+// an implementation that forwards to something slow (a network-backed
+// metrics backend, say) should do so asynchronously itself rather than
+// block the next sync pass.
+type MirrorSyncMetrics interface {
+	SyncStarted()
+	SyncCompleted(duration time.Duration, err error)
+	VersionFetched(provider addrs.Provider, version Version)
+}
+
+// MirrorSyncConfig configures a MirrorSyncDaemon.
+type MirrorSyncConfig struct {
+	// Source is queried for each target's available versions and package
+	// metadata.
+	Source Source
+
+	// MirrorDir is the local filesystem mirror directory to sync into,
+	// using the same layout FilesystemMirrorSource and AllAvailablePackages
+	// understand.
+	MirrorDir string
+
+	// Targets lists the providers to keep mirrored and the version
+	// constraint each one must satisfy.
+	Targets []MirrorSyncTarget
+
+	// TargetPlatform is the platform to request package metadata for.
+	TargetPlatform Platform
+
+	// Fetch is called once per newly-discovered, constraint-satisfying
+	// version, and is responsible for actually downloading and verifying
+	// the package and placing it into MirrorDir. A nil Fetch makes the
+	// daemon dry-run: it still detects and reports new versions, but
+	// doesn't try to mirror them.
+	Fetch func(ctx context.Context, provider addrs.Provider, version Version, meta PackageMeta) error
+
+	// Interval is the nominal time to wait between sync passes.
+	Interval time.Duration
+
+	// JitterFraction, in the range [0, 1], is the maximum fraction of
+	// Interval to randomly add to or subtract from each wait, so that many
+	// instances of this daemon started at the same time (as is typical
+	// when rolling out a fleet) don't all hammer the same upstream source
+	// in lockstep afterward.
+	JitterFraction float64
+
+	// Metrics, if non-nil, is notified of the outcome of each sync pass.
+	Metrics MirrorSyncMetrics
+
+	// OnNewVersion, if non-nil, is called once per newly-mirrored version,
+	// after Fetch (if any) succeeds. This is the hook webhook/event
+	// notification support is built on.
+	OnNewVersion func(provider addrs.Provider, version Version)
+
+	// Rand supplies the jitter in JitterFraction. A nil Rand gets a
+	// time-seeded one of its own.
+	Rand *rand.Rand
+}
+
+// MirrorSyncDaemon periodically syncs a configured set of providers from an
+// upstream Source into a local filesystem mirror, on a jittered schedule,
+// reporting progress through MirrorSyncConfig's Metrics and OnNewVersion
+// hooks.
+//
+// This is the long-running counterpart to a one-shot "terraform providers
+// mirror" invocation: construct one with NewMirrorSyncDaemon and call Run
+// from whatever long-lived process (a daemon mode, a hidden CLI command, a
+// scheduled job) should own the sync loop.
+type MirrorSyncDaemon struct {
+	config MirrorSyncConfig
+	rnd    *rand.Rand
+}
+
+// NewMirrorSyncDaemon constructs a MirrorSyncDaemon from the given
+// configuration.
+func NewMirrorSyncDaemon(config MirrorSyncConfig) *MirrorSyncDaemon {
+	rnd := config.Rand
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &MirrorSyncDaemon{config: config, rnd: rnd}
+}
+
+// Run performs sync passes at the configured, jittered interval until ctx
+// is cancelled, performing one pass immediately rather than waiting out
+// the first interval before doing any work.
+//
+// Run returns ctx.Err() once ctx is cancelled. A failed individual sync
+// pass is reported through Metrics.SyncCompleted rather than returned, so
+// that one bad pass (an upstream outage, say) doesn't bring the daemon
+// down; it simply tries again after the next interval.
+func (d *MirrorSyncDaemon) Run(ctx context.Context) error {
+	for {
+		d.syncOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.nextInterval()):
+		}
+	}
+}
+
+func (d *MirrorSyncDaemon) nextInterval() time.Duration {
+	if d.config.JitterFraction <= 0 {
+		return d.config.Interval
+	}
+	jitter := time.Duration(float64(d.config.Interval) * d.config.JitterFraction * (d.rnd.Float64()*2 - 1))
+	return d.config.Interval + jitter
+}
+
+func (d *MirrorSyncDaemon) syncOnce(ctx context.Context) {
+	start := time.Now()
+	if d.config.Metrics != nil {
+		d.config.Metrics.SyncStarted()
+	}
+	err := d.syncAllTargets(ctx)
+	if d.config.Metrics != nil {
+		d.config.Metrics.SyncCompleted(time.Since(start), err)
+	}
+}
+
+func (d *MirrorSyncDaemon) syncAllTargets(ctx context.Context) error {
+	existing, err := AllAvailablePackages(d.config.MirrorDir)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range d.config.Targets {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := d.syncTarget(ctx, target, existing[target.Provider]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *MirrorSyncDaemon) syncTarget(ctx context.Context, target MirrorSyncTarget, have VersionList) error {
+	upstream, err := d.config.Source.AvailableVersions(target.Provider)
+	if err != nil {
+		return err
+	}
+
+	acceptable := MeetingVersionConstraints(target.Constraints)
+	for _, v := range upstream {
+		if !acceptable.Has(v) || versionListHasVersion(have, v) {
+			continue
+		}
+
+		meta, err := d.config.Source.PackageMeta(target.Provider, v, d.config.TargetPlatform)
+		if err != nil {
+			return err
+		}
+
+		if d.config.Fetch != nil {
+			if err := d.config.Fetch(ctx, target.Provider, v, meta); err != nil {
+				return err
+			}
+		}
+
+		if d.config.Metrics != nil {
+			d.config.Metrics.VersionFetched(target.Provider, v)
+		}
+		if d.config.OnNewVersion != nil {
+			d.config.OnNewVersion(target.Provider, v)
+		}
+	}
+	return nil
+}
+
+func versionListHasVersion(list VersionList, v Version) bool {
+	for _, existing := range list {
+		if existing.String() == v.String() {
+			return true
+		}
+	}
+	return false
+}
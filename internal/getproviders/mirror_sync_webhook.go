@@ -0,0 +1,127 @@
+package getproviders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// NewVersionEvent describes a single newly-discovered provider version, in
+// the form WebhookNotifier and EventFileNotifier both emit.
+type NewVersionEvent struct {
+	Provider   string    `json:"provider"`
+	Version    string    `json:"version"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// WebhookNotifier POSTs a NewVersionEvent as JSON to a configured URL each
+// time it's called, for wiring up to MirrorSyncConfig.OnNewVersion so that
+// teams can trigger upgrade PR automation off of it.
+//
+// It matches the func(addrs.Provider, Version) signature OnNewVersion
+// expects via its Notify method, e.g.:
+//
+//	notifier := &WebhookNotifier{URL: "https://example.com/hooks/provider-updates"}
+//	config.OnNewVersion = notifier.Notify
+type WebhookNotifier struct {
+	// URL is the endpoint to POST each NewVersionEvent to.
+	URL string
+
+	// HTTPClient is used to make the request. A nil HTTPClient uses
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	// OnError, if non-nil, is called with any error encountered while
+	// delivering an event, since Notify itself has no return value to
+	// report one through. A nil OnError silently drops delivery failures,
+	// since a webhook outage shouldn't be allowed to interrupt mirror
+	// syncing.
+	OnError func(error)
+}
+
+// Notify delivers a NewVersionEvent for the given provider and version to
+// the configured webhook URL.
+func (n *WebhookNotifier) Notify(provider addrs.Provider, version Version) {
+	if err := n.deliver(provider, version); err != nil && n.OnError != nil {
+		n.OnError(err)
+	}
+}
+
+func (n *WebhookNotifier) deliver(provider addrs.Provider, version Version) error {
+	event := NewVersionEvent{
+		Provider:   provider.String(),
+		Version:    version.String(),
+		DetectedAt: time.Now(),
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	client := n.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("delivering webhook to %s: %s", n.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook at %s responded with status %s", n.URL, resp.Status)
+	}
+	return nil
+}
+
+// EventFileNotifier appends a NewVersionEvent as a line of JSON to a file
+// each time it's called, for environments where invoking an outbound
+// webhook isn't practical and a file watched by some other automation is
+// preferred instead.
+type EventFileNotifier struct {
+	// Path is the file to append events to, in JSON Lines format. It's
+	// created if it doesn't already exist.
+	Path string
+
+	// OnError, if non-nil, is called with any error encountered while
+	// writing an event. A nil OnError silently drops the failure.
+	OnError func(error)
+}
+
+// Notify appends a NewVersionEvent for the given provider and version to
+// the configured file.
+func (n *EventFileNotifier) Notify(provider addrs.Provider, version Version) {
+	if err := n.append(provider, version); err != nil && n.OnError != nil {
+		n.OnError(err)
+	}
+}
+
+func (n *EventFileNotifier) append(provider addrs.Provider, version Version) error {
+	event := NewVersionEvent{
+		Provider:   provider.String(),
+		Version:    version.String(),
+		DetectedAt: time.Now(),
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	f, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(line)
+	return err
+}
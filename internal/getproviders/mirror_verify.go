@@ -0,0 +1,174 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// MirrorVerificationProblem describes one provider package in a filesystem
+// mirror whose re-computed hash didn't match any hash LockFile recorded as
+// acceptable for it, as found by VerifyMirror.
+type MirrorVerificationProblem struct {
+	Provider addrs.Provider
+	Version  Version
+	Platform Platform
+
+	// WantHashes are the hashes the lock file recorded as acceptable for
+	// this provider version. GotHash is what VerifyMirror actually
+	// computed from the mirror's own copy of the package; it matches none
+	// of WantHashes, which is what made this a problem.
+	WantHashes []string
+	GotHash    string
+}
+
+// VerifyMirror re-hashes every package a filesystem mirror rooted at baseDir
+// has, for every platform, for every provider version lockFile also locks,
+// and returns one MirrorVerificationProblem for each whose recomputed hash
+// matches none of the hashes lockFile recorded as acceptable.
+//
+// A provider version the mirror has that lockFile doesn't mention at all is
+// not a problem as far as VerifyMirror is concerned -- that's ordinary for
+// a shared mirror serving many working directories with different lock
+// files -- and neither is a locked provider version the mirror doesn't
+// have, since that's simply not something VerifyMirror can check.
+// VerifyMirror only ever reports a package it was able to find and hash,
+// but whose hash didn't match what was expected.
+//
+// This is the building block for a "terraform providers verify" command: an
+// operator of an air-gapped mirror can run it on a schedule to catch
+// corruption or tampering that happened after packages were mirrored,
+// rather than only discovering it the next time someone tries to install
+// from the affected package.
+func VerifyMirror(baseDir string, lockFile *LockFile) ([]MirrorVerificationProblem, error) {
+	source := NewFilesystemMirrorSource(baseDir)
+	available, err := source.AllAvailablePackages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan mirror: %s", err)
+	}
+
+	var problems []MirrorVerificationProblem
+	for provider, versions := range available {
+		locked, ok := lockFile.Providers[provider]
+		if !ok {
+			continue
+		}
+		for _, version := range versions {
+			if version.String() != locked.Version.String() {
+				continue
+			}
+
+			typeDir := filepath.Join(baseDir, string(provider.Hostname), provider.Namespace, provider.Type)
+			platforms, err := platformsForMirroredVersion(typeDir, version)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list platforms for %s %s: %s", provider, version, err)
+			}
+
+			for _, platform := range platforms {
+				meta, err := source.PackageMeta(provider, version, platform)
+				if err != nil {
+					return nil, fmt.Errorf("failed to verify %s %s for %s: %s", provider, version, platform, err)
+				}
+
+				gotHash, wantHashes := mirroredPackageHashes(meta)
+				if hashListContains(locked.Hashes, wantHashes) {
+					continue
+				}
+				problems = append(problems, MirrorVerificationProblem{
+					Provider:   provider,
+					Version:    version,
+					Platform:   platform,
+					WantHashes: locked.Hashes,
+					GotHash:    gotHash,
+				})
+			}
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Provider != problems[j].Provider {
+			return problems[i].Provider.String() < problems[j].Provider.String()
+		}
+		if problems[i].Version.String() != problems[j].Version.String() {
+			return problems[i].Version.LessThan(problems[j].Version)
+		}
+		return problems[i].Platform.String() < problems[j].Platform.String()
+	})
+	return problems, nil
+}
+
+// mirroredPackageHashes returns the full set of hashes meta itself supports
+// -- its HashSchemeZip ("zh:") hash derived from SHA256Sum when that's set,
+// plus whatever meta.Hashes already carries, the same combination BuildPlan
+// uses -- along with a single representative hash string suitable for
+// reporting in a MirrorVerificationProblem.
+func mirroredPackageHashes(meta PackageMeta) (representative string, all []string) {
+	if meta.SHA256Sum != ([sha256.Size]byte{}) {
+		all = append(all, fmt.Sprintf("%s%x", HashSchemeZip, meta.SHA256Sum))
+	}
+	all = append(all, meta.Hashes...)
+	if len(all) > 0 {
+		representative = all[0]
+	}
+	return representative, all
+}
+
+// hashListContains reports whether any of candidates appears in want.
+func hashListContains(want []string, candidates []string) bool {
+	for _, c := range candidates {
+		for _, w := range want {
+			if c == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// platformsForMirroredVersion returns the platforms typeDir has a package
+// for version, recognizing both the unpacked layout (a <version>/<os_arch>
+// subdirectory per platform) and the packed layout (a
+// terraform-provider-<type>_<version>_<os>_<arch>.zip file directly in
+// typeDir), with a platform present in both only reported once.
+func platformsForMirroredVersion(typeDir string, version Version) ([]Platform, error) {
+	seen := make(map[Platform]bool)
+	var platforms []Platform
+	addIfNew := func(platform Platform) {
+		if seen[platform] {
+			return
+		}
+		seen[platform] = true
+		platforms = append(platforms, platform)
+	}
+
+	versionDir := filepath.Join(typeDir, version.String())
+	subEntries, err := readDirIfExists(versionDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", versionDir, err)
+	}
+	for _, sub := range subEntries {
+		if !sub.IsDir() {
+			continue
+		}
+		if platform, err := ParsePlatform(sub.Name()); err == nil {
+			addIfNew(platform)
+		}
+	}
+
+	typeEntries, err := readDirIfExists(typeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+	for _, entry := range typeEntries {
+		m := packageFilenamePattern.FindStringSubmatch(entry.Name())
+		if m == nil || m[2] != version.String() {
+			continue
+		}
+		addIfNew(Platform{OS: m[3], Arch: m[4]})
+	}
+
+	return platforms, nil
+}
@@ -0,0 +1,71 @@
+package getproviders
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestVerifyMirror(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 1)
+	defer close()
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+	version := versionMust(t, "0.0.0")
+	dir := filepath.Join(baseDir, "registry.terraform.io", "namespace0", "type0", "0.0.0", "linux_amd64")
+	hash, err := PackageDirHash(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	t.Run("matching hash", func(t *testing.T) {
+		lockFile := NewLockFile()
+		lockFile.Providers[provider] = LockedProvider{
+			Version: version,
+			Hashes:  []string{hash},
+		}
+
+		problems, err := VerifyMirror(baseDir, lockFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(problems) != 0 {
+			t.Fatalf("expected no problems, got %#v", problems)
+		}
+	})
+
+	t.Run("mismatched hash", func(t *testing.T) {
+		lockFile := NewLockFile()
+		lockFile.Providers[provider] = LockedProvider{
+			Version: version,
+			Hashes:  []string{"h1:not-the-right-hash"},
+		}
+
+		problems, err := VerifyMirror(baseDir, lockFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(problems) != 1 {
+			t.Fatalf("wrong number of problems: got %d, want 1: %#v", len(problems), problems)
+		}
+		got := problems[0]
+		if got.Provider != provider || got.Version.String() != "0.0.0" || got.Platform != (Platform{OS: "linux", Arch: "amd64"}) {
+			t.Errorf("wrong problem: %#v", got)
+		}
+		if got.GotHash != hash {
+			t.Errorf("wrong GotHash: got %q, want %q", got.GotHash, hash)
+		}
+	})
+
+	t.Run("unlocked provider is not a problem", func(t *testing.T) {
+		lockFile := NewLockFile()
+		problems, err := VerifyMirror(baseDir, lockFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(problems) != 0 {
+			t.Fatalf("expected no problems, got %#v", problems)
+		}
+	})
+}
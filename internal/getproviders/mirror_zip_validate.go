@@ -0,0 +1,149 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"runtime/pprof"
+	"strings"
+)
+
+// packedArchiveExecutablePrefix is the filename prefix every provider
+// distribution archive's executable is expected to have, regardless of
+// platform or any ".exe" suffix Windows builds add.
+const packedArchiveExecutablePrefix = "terraform-provider-"
+
+// ValidatePackedArchives walks baseDir's hostname/namespace/type/packed-zip
+// layout -- the same one AllAvailablePackages recognizes -- opening every
+// packed .zip it finds and confirming it's a readable archive containing a
+// terraform-provider-* executable, rather than trusting the filename alone.
+//
+// A mirror populated by an interrupted copy or sync can end up with a zip
+// file present but truncated or otherwise corrupt; AllAvailablePackages has
+// no reason to notice this, since it only inspects filenames, so a broken
+// package isn't caught until something tries to extract it, possibly well
+// after a plan already depended on it being there. This is a separate,
+// opt-in pass for catching that earlier, since opening and reading every
+// packed archive is far more expensive than the filename-only scan.
+//
+// It returns a MirrorScanProblem for each packed archive that fails to
+// open or doesn't contain a provider executable; a nil result means every
+// packed archive found validated successfully. Unpacked packages and
+// anything scanMirrorVersions already wouldn't recognize as a packed
+// archive are silently ignored, consistent with the plain scan.
+func ValidatePackedArchives(baseDir string) ([]MirrorScanProblem, error) {
+	var problems []MirrorScanProblem
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("getproviders.scan_phase", "validate_mirror_zips"), func(context.Context) {
+		problems, err = validatePackedArchivesInMirror(baseDir)
+	})
+	return problems, err
+}
+
+func validatePackedArchivesInMirror(baseDir string) ([]MirrorScanProblem, error) {
+	var problems []MirrorScanProblem
+
+	hostEntries, err := readDirIfExists(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", baseDir, err)
+	}
+	for _, hostEntry := range hostEntries {
+		if !hostEntry.IsDir() {
+			continue
+		}
+		hostPath := filepath.Join(baseDir, hostEntry.Name())
+
+		namespaceEntries, err := readDirIfExists(hostPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", hostPath, err)
+		}
+		for _, namespaceEntry := range namespaceEntries {
+			if !namespaceEntry.IsDir() {
+				continue
+			}
+			namespacePath := filepath.Join(hostPath, namespaceEntry.Name())
+
+			typeEntries, err := readDirIfExists(namespacePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", namespacePath, err)
+			}
+			for _, typeEntry := range typeEntries {
+				if !typeEntry.IsDir() {
+					continue
+				}
+				typePath := filepath.Join(namespacePath, typeEntry.Name())
+
+				typeProblems, err := validatePackedArchivesInTypeDir(typePath)
+				if err != nil {
+					return nil, err
+				}
+				problems = append(problems, typeProblems...)
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+func validatePackedArchivesInTypeDir(typeDir string) ([]MirrorScanProblem, error) {
+	entries, err := readDirIfExists(typeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", typeDir, err)
+	}
+
+	var problems []MirrorScanProblem
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if packageFilenamePattern.FindStringSubmatch(entry.Name()) == nil {
+			continue
+		}
+
+		archivePath := filepath.Join(typeDir, entry.Name())
+		if reason := validatePackedArchive(archivePath); reason != "" {
+			problems = append(problems, MirrorScanProblem{Path: archivePath, Reason: reason})
+		}
+	}
+	return problems, nil
+}
+
+// validatePackedArchive opens path as a zip archive and confirms it
+// contains an entry that looks like a provider executable, reading that
+// entry fully to let the zip reader's own CRC-32 check catch truncation or
+// corruption the directory listing alone wouldn't reveal. It returns a
+// non-empty reason on any problem, or an empty string if the archive
+// validated successfully.
+func validatePackedArchive(path string) string {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Sprintf("corrupt or unreadable zip archive: %s", err)
+	}
+	defer r.Close()
+
+	var executable *zip.File
+	for _, f := range r.File {
+		if strings.HasPrefix(filepath.Base(f.Name), packedArchiveExecutablePrefix) {
+			executable = f
+			break
+		}
+	}
+	if executable == nil {
+		return fmt.Sprintf("zip archive does not contain a %s* executable", packedArchiveExecutablePrefix)
+	}
+
+	rc, err := executable.Open()
+	if err != nil {
+		return fmt.Sprintf("failed to read %s from zip archive: %s", executable.Name, err)
+	}
+	defer rc.Close()
+
+	if _, err := io.Copy(ioutil.Discard, rc); err != nil {
+		return fmt.Sprintf("zip archive is truncated or corrupt: failed to read %s: %s", executable.Name, err)
+	}
+
+	return ""
+}
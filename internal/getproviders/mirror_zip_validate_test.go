@@ -0,0 +1,104 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestPackedArchive(t *testing.T, typeDir, filename string, entries map[string]string) {
+	t.Helper()
+
+	if err := os.MkdirAll(typeDir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %s", typeDir, err)
+	}
+
+	f, err := os.Create(filepath.Join(typeDir, filename))
+	if err != nil {
+		t.Fatalf("failed to create %s: %s", filename, err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry %s: %s", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write zip entry %s: %s", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %s", err)
+	}
+}
+
+func TestValidatePackedArchivesClean(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-validate-zips")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "null")
+	writeTestPackedArchive(t, typeDir, "terraform-provider-null_1.0.0_linux_amd64.zip", map[string]string{
+		"terraform-provider-null_v1.0.0_x5": "fake binary",
+	})
+
+	problems, err := ValidatePackedArchives(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("unexpected problems in a clean mirror: %v", problems)
+	}
+}
+
+func TestValidatePackedArchivesMissingExecutable(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-validate-zips")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "null")
+	writeTestPackedArchive(t, typeDir, "terraform-provider-null_1.0.0_linux_amd64.zip", map[string]string{
+		"README.txt": "oops, no binary in here",
+	})
+
+	problems, err := ValidatePackedArchives(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected exactly one problem, got %v", problems)
+	}
+}
+
+func TestValidatePackedArchivesCorrupt(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-validate-zips")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "hashicorp", "null")
+	if err := os.MkdirAll(typeDir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %s", typeDir, err)
+	}
+	path := filepath.Join(typeDir, "terraform-provider-null_1.0.0_linux_amd64.zip")
+	if err := ioutil.WriteFile(path, []byte("this is not a zip file"), 0644); err != nil {
+		t.Fatalf("failed to write truncated archive: %s", err)
+	}
+
+	problems, err := ValidatePackedArchives(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected exactly one problem, got %v", problems)
+	}
+}
@@ -25,6 +25,7 @@ import (
 type MultiSource []MultiSourceSelector
 
 var _ Source = MultiSource(nil)
+var _ EnumerableSource = MultiSource(nil)
 
 // AvailableVersions retrieves all of the versions of the given provider
 // that are available across all of the underlying selectors, while respecting
@@ -41,6 +42,67 @@ func (s MultiSource) PackageMeta(provider addrs.Provider, version Version, targe
 	panic("MultiSource.PackageMeta not yet implemented")
 }
 
+// AllAvailablePackages implements EnumerableSource by unioning the result
+// of calling AllAvailablePackages on each underlying selector that itself
+// implements EnumerableSource, filtered by that selector's matching
+// patterns, and skipping any selector that doesn't -- so a MultiSource
+// made up entirely of enumerable selectors (such as filesystem mirrors) is
+// itself enumerable, while one that also mixes in a per-provider-only
+// remote source still is, just with a partial view limited to what its
+// enumerable selectors can see.
+//
+// A provider version visible through more than one selector is reported
+// only once.
+func (s MultiSource) AllAvailablePackages() (map[addrs.Provider]VersionList, error) {
+	ret := make(map[addrs.Provider]VersionList)
+	for _, selector := range s {
+		enumerable, ok := selector.Source.(EnumerableSource)
+		if !ok {
+			continue
+		}
+
+		packages, err := enumerable.AllAvailablePackages()
+		if err != nil {
+			return nil, err
+		}
+		for provider, versions := range packages {
+			if !selector.CanHandleProvider(provider) {
+				continue
+			}
+			existing, ok := ret[provider]
+			if !ok {
+				ret[provider] = versions
+				continue
+			}
+			ret[provider] = unionVersionLists(existing, versions)
+		}
+	}
+	for provider, versions := range ret {
+		versions.Sort()
+		ret[provider] = versions
+	}
+	return ret, nil
+}
+
+// unionVersionLists combines a and b into a single de-duplicated,
+// unsorted VersionList.
+func unionVersionLists(a, b VersionList) VersionList {
+	seen := make(map[string]bool, len(a))
+	ret := make(VersionList, 0, len(a)+len(b))
+	for _, v := range a {
+		seen[v.String()] = true
+		ret = append(ret, v)
+	}
+	for _, v := range b {
+		if seen[v.String()] {
+			continue
+		}
+		seen[v.String()] = true
+		ret = append(ret, v)
+	}
+	return ret
+}
+
 // MultiSourceSelector is an element of the source selection configuration on
 // MultiSource. A MultiSource has zero or more of these to configure which
 // underlying sources it should consult for a given provider.
@@ -76,7 +138,7 @@ func ParseMultiSourceMatchingPatterns(strs []string) (MultiSourceMatchingPattern
 		if len(parts) < 2 || len(parts) > 3 {
 			return nil, fmt.Errorf("invalid provider matching pattern %q: must have either two or three slash-separated segments", str)
 		}
-		host := defaultRegistryHost
+		host := DefaultRegistryHost
 		explicitHost := len(parts) == 3
 		if explicitHost {
 			givenHost := parts[0]
@@ -154,9 +216,25 @@ func (ps MultiSourceMatchingPatterns) MatchesProvider(addr addrs.Provider) bool
 // anywhere else.
 const Wildcard string = "*"
 
-// We'll read the default registry host from over in the addrs package, to
-// avoid duplicating it. A "default" provider uses the default registry host
-// by definition.
-var defaultRegistryHost = addrs.NewDefaultProvider("placeholder").Hostname
+// DefaultRegistryHost is the hostname used for provider addresses that do
+// not have an explicit hostname, and the host that unqualified legacy
+// provider names are resolved against.
+//
+// It defaults to the hostname defined by the addrs package, but can be
+// overridden via SetDefaultRegistryHost to support fully self-hosted
+// installations where unqualified provider addresses should resolve to an
+// internal registry instead of the public one.
+var DefaultRegistryHost = addrs.NewDefaultProvider("placeholder").Hostname
+
+// SetDefaultRegistryHost overrides DefaultRegistryHost for the remaining
+// lifetime of the process.
+//
+// This is intended to be called once during startup, based on CLI
+// configuration, before any provider installation source objects are
+// constructed. It is not safe to call concurrently with any use of
+// DefaultRegistryHost elsewhere in this package.
+func SetDefaultRegistryHost(host svchost.Hostname) {
+	DefaultRegistryHost = host
+}
 
 var validProviderNamePattern = regexp.MustCompile("^[a-zA-Z0-9_-]+|\\*$")
@@ -0,0 +1,94 @@
+package getproviders
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// OrderedProgress renders concurrent per-provider installation progress to
+// an underlying io.Writer deterministically, in a fixed order decided up
+// front, rather than in whatever order the goroutines racing to install
+// providers happen to finish or emit lines in -- since interleaved or
+// run-order-dependent progress lines are unreadable in a plain terminal and
+// unusable in a CI log that can't redraw previous output.
+//
+// Each provider's lines are buffered until every provider ordered before it
+// has been marked done, at which point they're flushed to Out as one
+// contiguous block. A provider that finishes early still waits for its
+// predecessors before its output appears, but it doesn't block the
+// providers after it from buffering their own output in the meantime.
+type OrderedProgress struct {
+	// Out is where flushed regions are written, in order.
+	Out io.Writer
+
+	mu      sync.Mutex
+	order   []addrs.Provider
+	index   map[addrs.Provider]int
+	buffers []bytes.Buffer
+	done    []bool
+	flushed int
+}
+
+// NewOrderedProgress constructs an OrderedProgress that will render lines
+// for each of order's providers, in that order, to out.
+func NewOrderedProgress(out io.Writer, order []addrs.Provider) *OrderedProgress {
+	index := make(map[addrs.Provider]int, len(order))
+	for i, provider := range order {
+		index[provider] = i
+	}
+	return &OrderedProgress{
+		Out:     out,
+		order:   order,
+		index:   index,
+		buffers: make([]bytes.Buffer, len(order)),
+		done:    make([]bool, len(order)),
+	}
+}
+
+// Printf appends a formatted line to provider's buffered region, adding a
+// trailing newline if format doesn't already end with one.
+//
+// It's safe to call concurrently, including for different providers and
+// concurrently with Done, but has no effect for a provider that wasn't
+// included in the order passed to NewOrderedProgress.
+func (p *OrderedProgress) Printf(provider addrs.Provider, format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i, ok := p.index[provider]
+	if !ok {
+		return
+	}
+	fmt.Fprintf(&p.buffers[i], format, args...)
+	if format == "" || format[len(format)-1] != '\n' {
+		p.buffers[i].WriteByte('\n')
+	}
+}
+
+// Done marks provider's region as complete. If provider is now the
+// earliest not-yet-flushed provider in the order, its region -- and any
+// already-done providers immediately following it -- are flushed to Out.
+//
+// It's safe to call concurrently, and has no effect for a provider that
+// wasn't included in the order passed to NewOrderedProgress, or that was
+// already marked done.
+func (p *OrderedProgress) Done(provider addrs.Provider) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i, ok := p.index[provider]
+	if !ok {
+		return
+	}
+	p.done[i] = true
+	p.flush()
+}
+
+func (p *OrderedProgress) flush() {
+	for p.flushed < len(p.order) && p.done[p.flushed] {
+		io.Copy(p.Out, &p.buffers[p.flushed])
+		p.flushed++
+	}
+}
@@ -0,0 +1,67 @@
+package getproviders
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestOrderedProgress(t *testing.T) {
+	providerA := addrs.NewProvider(DefaultRegistryHost, "namespace0", "a")
+	providerB := addrs.NewProvider(DefaultRegistryHost, "namespace0", "b")
+	providerC := addrs.NewProvider(DefaultRegistryHost, "namespace0", "c")
+
+	var out bytes.Buffer
+	p := NewOrderedProgress(&out, []addrs.Provider{providerA, providerB, providerC})
+
+	// Finish in reverse order, simulating concurrent installs that don't
+	// complete in the order they were requested.
+	p.Printf(providerC, "installing %s", providerC)
+	p.Done(providerC)
+	p.Printf(providerB, "installing %s", providerB)
+	p.Done(providerB)
+
+	if out.Len() != 0 {
+		t.Fatalf("expected nothing flushed yet, got %q", out.String())
+	}
+
+	p.Printf(providerA, "installing %s", providerA)
+	p.Done(providerA)
+
+	got := out.String()
+	want := "installing " + providerA.String() + "\ninstalling " + providerB.String() + "\ninstalling " + providerC.String() + "\n"
+	if got != want {
+		t.Errorf("wrong output\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestOrderedProgressConcurrent(t *testing.T) {
+	providers := make([]addrs.Provider, 10)
+	for i := range providers {
+		providers[i] = addrs.NewProvider(DefaultRegistryHost, "namespace0", string(rune('a'+i)))
+	}
+
+	var out bytes.Buffer
+	p := NewOrderedProgress(&out, providers)
+
+	var wg sync.WaitGroup
+	for _, provider := range providers {
+		wg.Add(1)
+		go func(provider addrs.Provider) {
+			defer wg.Done()
+			p.Printf(provider, "done with %s", provider)
+			p.Done(provider)
+		}(provider)
+	}
+	wg.Wait()
+
+	want := ""
+	for _, provider := range providers {
+		want += "done with " + provider.String() + "\n"
+	}
+	if out.String() != want {
+		t.Errorf("wrong output\ngot:  %q\nwant: %q", out.String(), want)
+	}
+}
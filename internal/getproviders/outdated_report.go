@@ -0,0 +1,79 @@
+package getproviders
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// OutdatedProvider describes one provider recorded in a lock file for
+// which a newer version is available, for rendering in a report that
+// helps a team decide whether adopting it is safe.
+type OutdatedProvider struct {
+	Provider      addrs.Provider
+	LockedVersion Version
+	NewestVersion Version
+
+	// NewestPlatforms lists the platforms the source reports NewestVersion
+	// as supporting, if source implements PlatformCoverageSource, or nil
+	// if it doesn't -- in which case a caller should render platform
+	// coverage as unknown rather than assume NewestVersion is missing
+	// platforms the locked version has.
+	NewestPlatforms []Platform
+
+	// NewestSize is NewestVersion's package size in bytes for
+	// CurrentPlatform, and NewestSizeKnown is false if PackageSize
+	// couldn't determine it.
+	NewestSize      int64
+	NewestSizeKnown bool
+}
+
+// ReportOutdatedProviders compares every provider in lock against the
+// newest version source reports as available, regardless of any version
+// constraint -- since a lock file doesn't retain the constraint it was
+// last resolved against -- and returns one OutdatedProvider for each that
+// has a newer version, enriched with the newest version's package size
+// and platform coverage so a team reviewing the report can spot a
+// candidate that would drop support for a platform they depend on, or
+// balloon in size, before adopting it.
+//
+// httpClient is used for any HEAD request PackageSize needs to make; a nil
+// value uses http.DefaultClient.
+func ReportOutdatedProviders(source Source, lock *LockFile, httpClient *http.Client) ([]OutdatedProvider, error) {
+	var report []OutdatedProvider
+	for provider, locked := range lock.Providers {
+		newest, err := newestAllowedVersion(source, provider, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !newest.GreaterThan(locked.Version) {
+			continue
+		}
+
+		outdated := OutdatedProvider{
+			Provider:      provider,
+			LockedVersion: locked.Version,
+			NewestVersion: newest,
+		}
+
+		if coverage, ok := source.(PlatformCoverageSource); ok {
+			platforms, err := coverage.PlatformsForVersion(provider, newest)
+			if err != nil {
+				return nil, err
+			}
+			outdated.NewestPlatforms = platforms
+		}
+
+		meta, err := source.PackageMeta(provider, newest, CurrentPlatform)
+		if err == nil {
+			size, ok, sizeErr := PackageSize(httpClient, meta)
+			if sizeErr == nil && ok {
+				outdated.NewestSize = size
+				outdated.NewestSizeKnown = true
+			}
+		}
+
+		report = append(report, outdated)
+	}
+	return report, nil
+}
@@ -0,0 +1,51 @@
+package getproviders
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestReportOutdatedProviders(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	source := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	lock := NewLockFile()
+	lock.Providers[provider] = LockedProvider{Version: versionMust(t, "0.0.0")}
+
+	report, err := ReportOutdatedProviders(source, lock, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(report) != 1 {
+		t.Fatalf("wrong report length: got %d, want 1", len(report))
+	}
+	if got, want := report[0].NewestVersion.String(), "1.0.0"; got != want {
+		t.Errorf("wrong newest version: got %s, want %s", got, want)
+	}
+	if got, want := report[0].LockedVersion.String(), "0.0.0"; got != want {
+		t.Errorf("wrong locked version: got %s, want %s", got, want)
+	}
+}
+
+func TestReportOutdatedProvidersUpToDate(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	source := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	lock := NewLockFile()
+	lock.Providers[provider] = LockedProvider{Version: versionMust(t, "1.0.0")}
+
+	report, err := ReportOutdatedProviders(source, lock, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(report) != 0 {
+		t.Fatalf("expected no outdated providers, got %v", report)
+	}
+}
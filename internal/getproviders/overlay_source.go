@@ -0,0 +1,119 @@
+package getproviders
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// ProviderOverlay redirects one provider address to another, optionally
+// restricting which of the target's versions are considered, for the
+// duration of a single run.
+//
+// This exists for testing a forked or locally-patched build of a provider
+// without editing the configuration or lock file that names the original
+// address -- for example, pointing hashicorp/aws at a development
+// namespace while a fix is validated, without that substitution leaking
+// into anything that gets committed.
+type ProviderOverlay struct {
+	// Target is the provider address to consult instead of the overlaid
+	// address.
+	Target addrs.Provider
+
+	// Constraints, if non-nil, limits which of Target's versions are
+	// considered available. A nil value imposes no restriction beyond
+	// whatever Target itself offers.
+	Constraints VersionConstraints
+}
+
+// OverlaySource is a Source that wraps another Source and substitutes a
+// different provider address -- and optionally a narrower version
+// selection -- for specific providers, as configured by a set of
+// ProviderOverlay entries.
+//
+// An OverlaySource is intended to be constructed fresh for a single run
+// from a small, explicit file the user opts into, not stored anywhere
+// that would cause it to silently apply across runs.
+type OverlaySource struct {
+	underlying Source
+	overlays   map[addrs.Provider]ProviderOverlay
+}
+
+var _ Source = (*OverlaySource)(nil)
+
+// NewOverlaySource constructs a new OverlaySource wrapping the given
+// underlying source, with the given overlaid providers.
+//
+// Providers not present as a key in overlays are passed through to the
+// underlying source unchanged.
+func NewOverlaySource(underlying Source, overlays map[addrs.Provider]ProviderOverlay) *OverlaySource {
+	return &OverlaySource{
+		underlying: underlying,
+		overlays:   overlays,
+	}
+}
+
+// IsOverlaid returns true if provider has an overlay configured, so that a
+// caller writing a lock file can skip recording an entry for it -- an
+// overlay is a transient substitution for the current run, not a
+// selection that should be remembered.
+func (s *OverlaySource) IsOverlaid(provider addrs.Provider) bool {
+	_, ok := s.overlays[provider]
+	return ok
+}
+
+// Describe returns a human-readable summary of provider's overlay, if it
+// has one, suitable for including in startup output so that an overlay
+// in effect is never silently invisible to whoever is running Terraform.
+//
+// The second return value is false if provider has no overlay configured.
+func (s *OverlaySource) Describe(provider addrs.Provider) (string, bool) {
+	overlay, ok := s.overlays[provider]
+	if !ok {
+		return "", false
+	}
+	if len(overlay.Constraints) == 0 {
+		return fmt.Sprintf("provider %s is overlaid to %s for this run", provider, overlay.Target), true
+	}
+	return fmt.Sprintf(
+		"provider %s is overlaid to %s %s for this run",
+		provider, overlay.Target, FormatVersionConstraints(overlay.Constraints),
+	), true
+}
+
+// AvailableVersions returns the versions available for provider, consulting
+// its overlay's target and constraints if it has one, or passing the
+// request through to the underlying source otherwise.
+func (s *OverlaySource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	overlay, ok := s.overlays[provider]
+	if !ok {
+		return s.underlying.AvailableVersions(provider)
+	}
+
+	all, err := s.underlying.AvailableVersions(overlay.Target)
+	if err != nil {
+		return nil, err
+	}
+	if len(overlay.Constraints) == 0 {
+		return all, nil
+	}
+
+	allowed := MeetingVersionConstraints(overlay.Constraints)
+	ret := make(VersionList, 0, len(all))
+	for _, v := range all {
+		if allowed.Has(v) {
+			ret = append(ret, v)
+		}
+	}
+	return ret, nil
+}
+
+// PackageMeta returns package metadata for provider at version, redirected
+// to its overlay's target if it has one.
+func (s *OverlaySource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	overlay, ok := s.overlays[provider]
+	if !ok {
+		return s.underlying.PackageMeta(provider, version, target)
+	}
+	return s.underlying.PackageMeta(overlay.Target, version, target)
+}
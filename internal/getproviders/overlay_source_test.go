@@ -0,0 +1,88 @@
+package getproviders
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestOverlaySource(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 2, 3)
+	defer close()
+
+	underlying := NewFilesystemMirrorSource(baseDir)
+	original := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+	fork := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type1")
+
+	constraints, err := ParseVersionConstraints("<= 1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	source := NewOverlaySource(underlying, map[addrs.Provider]ProviderOverlay{
+		original: {
+			Target:      fork,
+			Constraints: constraints,
+		},
+	})
+
+	if !source.IsOverlaid(original) {
+		t.Errorf("expected %s to be reported as overlaid", original)
+	}
+	if source.IsOverlaid(fork) {
+		t.Errorf("did not expect %s to be reported as overlaid", fork)
+	}
+
+	desc, ok := source.Describe(original)
+	if !ok {
+		t.Fatalf("expected a description for the overlaid provider")
+	}
+	if desc == "" {
+		t.Errorf("expected a non-empty description")
+	}
+
+	got, err := source.AvailableVersions(original)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// 0.0.0 is the go-versions "unspecified" version, which any constraint
+	// excludes, so only 1.0.0 of the fork's 0.0.0, 1.0.0 and 2.0.0 remains.
+	want := VersionList{versionMust(t, "1.0.0")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong versions\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	meta, err := source.PackageMeta(original, versionMust(t, "1.0.0"), Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantFilename := "terraform-provider-type1_1.0.0_linux_amd64.zip"
+	if meta.Filename != wantFilename {
+		t.Errorf("wrong filename: got %s, want %s (package meta was not redirected to the overlay target)", meta.Filename, wantFilename)
+	}
+}
+
+func TestOverlaySourcePassthrough(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	underlying := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	source := NewOverlaySource(underlying, nil)
+	if source.IsOverlaid(provider) {
+		t.Errorf("did not expect %s to be reported as overlaid", provider)
+	}
+	if _, ok := source.Describe(provider); ok {
+		t.Errorf("did not expect a description for a provider with no overlay")
+	}
+
+	got, err := source.AvailableVersions(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("wrong number of versions: got %d, want 2", len(got))
+	}
+}
@@ -0,0 +1,129 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// DefaultOverridesFilename is the name ReadOverridesFile looks for within a
+// working directory when no other filename is given. It's a dotfile so that
+// it doesn't show up in an ordinary directory listing alongside the
+// configuration it overrides, the same reasoning as Terraform's other
+// working-directory-scoped dotfiles.
+const DefaultOverridesFilename = ".terraform-overrides.json"
+
+// Overrides represents a set of per-working-directory provider pins that
+// take precedence over whatever version constraints and hashes would
+// otherwise be selected from configuration and the lock file.
+//
+// Overrides are intended as an emergency escape hatch for situations such
+// as rolling back to a known-good provider version without having to edit
+// shared module code, so every override is reported back to the caller as
+// a warning to discourage leaving one in place longer than necessary.
+type Overrides struct {
+	Providers map[addrs.Provider]LockedProvider
+}
+
+// NewOverrides constructs an empty set of overrides, ready to have entries
+// added to it.
+func NewOverrides() *Overrides {
+	return &Overrides{
+		Providers: make(map[addrs.Provider]LockedProvider),
+	}
+}
+
+// Override returns the pinned version and hashes configured for the given
+// provider, if any, along with a warning describing the override that
+// callers should surface to the user.
+//
+// The second return value is the empty string and the third return value
+// is false if there is no override configured for the given provider.
+func (o *Overrides) Override(provider addrs.Provider) (LockedProvider, string, bool) {
+	if o == nil {
+		return LockedProvider{}, "", false
+	}
+	locked, ok := o.Providers[provider]
+	if !ok {
+		return LockedProvider{}, "", false
+	}
+
+	warning := fmt.Sprintf(
+		"provider %s is pinned to %s by a local override file, ignoring any version constraints given in configuration; this override should be removed once it is no longer needed",
+		provider, locked.Version,
+	)
+	return locked, warning, true
+}
+
+// overridesFileJSON is the on-disk representation of an overrides file: a
+// JSON object keyed by provider source address, each giving the exact
+// version (and, optionally, the hashes) that provider is pinned to.
+//
+//	{
+//	  "registry.terraform.io/hashicorp/aws": {
+//	    "version": "4.0.0",
+//	    "hashes": ["h1:0123456789012345678901234567890123456789012="]
+//	  }
+//	}
+type overridesFileJSON map[string]overrideEntryJSON
+
+type overrideEntryJSON struct {
+	Version string   `json:"version"`
+	Hashes  []string `json:"hashes,omitempty"`
+}
+
+// ParseOverrides reads a JSON-encoded overrides document from r, in the same
+// format ReadOverridesFile loads from a working directory's override file,
+// and returns the equivalent Overrides value.
+func ParseOverrides(r io.Reader) (*Overrides, error) {
+	var raw overridesFileJSON
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid provider overrides file: %s", err)
+	}
+
+	overrides := NewOverrides()
+	for providerStr, entry := range raw {
+		provider, diags := addrs.ParseProviderSourceString(providerStr)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("invalid provider address %q in overrides file: %s", providerStr, diags.Err())
+		}
+		version, err := ParseVersion(entry.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q for %s in overrides file: %s", entry.Version, providerStr, err)
+		}
+		overrides.Providers[provider] = LockedProvider{
+			Version: version,
+			Hashes:  entry.Hashes,
+		}
+	}
+	return overrides, nil
+}
+
+// ReadOverridesFile loads the provider overrides configured for workingDir,
+// by looking for DefaultOverridesFilename directly inside it.
+//
+// A working directory with no override file is not an error: it simply has
+// no overrides, reported back as a non-nil, empty Overrides so that callers
+// can treat the result the same way regardless of whether a file was
+// present.
+func ReadOverridesFile(workingDir string) (*Overrides, error) {
+	path := filepath.Join(workingDir, DefaultOverridesFilename)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewOverrides(), nil
+		}
+		return nil, fmt.Errorf("failed to read provider overrides file %s: %s", path, err)
+	}
+	defer f.Close()
+
+	overrides, err := ParseOverrides(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return overrides, nil
+}
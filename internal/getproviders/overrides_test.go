@@ -0,0 +1,98 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestParseOverrides(t *testing.T) {
+	r := strings.NewReader(`{
+		"registry.terraform.io/hashicorp/aws": {
+			"version": "4.0.0",
+			"hashes": ["h1:0123456789012345678901234567890123456789012="]
+		}
+	}`)
+
+	overrides, err := ParseOverrides(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "aws")
+	locked, warning, ok := overrides.Override(provider)
+	if !ok {
+		t.Fatalf("expected an override for %s", provider)
+	}
+	if locked.Version.String() != "4.0.0" {
+		t.Errorf("wrong version: got %s, want 4.0.0", locked.Version)
+	}
+	if len(locked.Hashes) != 1 || locked.Hashes[0] != "h1:0123456789012345678901234567890123456789012=" {
+		t.Errorf("wrong hashes: got %v", locked.Hashes)
+	}
+	if warning == "" {
+		t.Errorf("expected a non-empty warning")
+	}
+
+	other := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	if _, _, ok := overrides.Override(other); ok {
+		t.Errorf("did not expect an override for %s", other)
+	}
+}
+
+func TestParseOverridesInvalidProvider(t *testing.T) {
+	r := strings.NewReader(`{"not a valid provider address": {"version": "1.0.0"}}`)
+	if _, err := ParseOverrides(r); err == nil {
+		t.Fatalf("expected an error for an invalid provider address")
+	}
+}
+
+func TestParseOverridesInvalidVersion(t *testing.T) {
+	r := strings.NewReader(`{"registry.terraform.io/hashicorp/aws": {"version": "not-a-version"}}`)
+	if _, err := ParseOverrides(r); err == nil {
+		t.Fatalf("expected an error for an invalid version")
+	}
+}
+
+func TestReadOverridesFileMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-getproviders-overrides")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	overrides, err := ReadOverridesFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(overrides.Providers) != 0 {
+		t.Errorf("expected no overrides for a working directory with no overrides file")
+	}
+}
+
+func TestReadOverridesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-getproviders-overrides")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `{"registry.terraform.io/hashicorp/null": {"version": "3.1.0"}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, DefaultOverridesFilename), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write overrides file: %s", err)
+	}
+
+	overrides, err := ReadOverridesFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	if _, _, ok := overrides.Override(provider); !ok {
+		t.Fatalf("expected an override for %s", provider)
+	}
+}
@@ -0,0 +1,265 @@
+package getproviders
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+// PackageAuthentication is implemented by the various checks that can be
+// performed against a downloaded provider package to establish how much it
+// can be trusted. A PackageMeta can carry one or more of these, depending
+// on what verification material its Source was able to provide, and they
+// can be combined with PackageAuthenticationAll.
+//
+// Defining verification this way, rather than as a fixed sequence of steps
+// inside each Source, means a new scheme -- a sigstore check, a private
+// registry's own checksum file, a corporate policy check -- can be added
+// without any existing Source needing to change.
+type PackageAuthentication interface {
+	// AuthenticatePackage checks localLocation, the package as it exists on
+	// local disk after download (either a PackageLocalArchive or a
+	// PackageLocalDir), and returns a short human-readable description of
+	// what it verified if successful, or an error explaining why it
+	// didn't.
+	AuthenticatePackage(localLocation PackageLocation) (string, error)
+}
+
+// PackageAuthenticationFunc adapts a plain function to PackageAuthentication,
+// so that a simple custom verifier -- a corporate policy check consulting
+// an internal allow-list, for example -- doesn't need its own named type.
+type PackageAuthenticationFunc func(localLocation PackageLocation) (string, error)
+
+func (f PackageAuthenticationFunc) AuthenticatePackage(localLocation PackageLocation) (string, error) {
+	return f(localLocation)
+}
+
+// PackageAuthenticationAll combines several PackageAuthentication checks
+// into one that requires all of them to succeed, stopping at the first
+// failure. Its description is the concatenation of each check's
+// description, in order.
+func PackageAuthenticationAll(checks ...PackageAuthentication) PackageAuthentication {
+	return packageAuthenticationAll(checks)
+}
+
+type packageAuthenticationAll []PackageAuthentication
+
+func (all packageAuthenticationAll) AuthenticatePackage(localLocation PackageLocation) (string, error) {
+	var descriptions []string
+	for _, check := range all {
+		desc, err := check.AuthenticatePackage(localLocation)
+		if err != nil {
+			return "", err
+		}
+		if desc != "" {
+			descriptions = append(descriptions, desc)
+		}
+	}
+	return strings.Join(descriptions, "; "), nil
+}
+
+// NewChecksumAuthentication returns a PackageAuthentication that checks a
+// downloaded package archive's SHA256 sum against an expected value. This
+// is the most basic level of verification, achievable for any Source that
+// can report a checksum at all.
+func NewChecksumAuthentication(wantSHA256Sum [sha256.Size]byte) PackageAuthentication {
+	return checksumAuthentication{wantSHA256Sum}
+}
+
+type checksumAuthentication struct {
+	want [sha256.Size]byte
+}
+
+func (a checksumAuthentication) AuthenticatePackage(localLocation PackageLocation) (string, error) {
+	archive, ok := localLocation.(PackageLocalArchive)
+	if !ok {
+		return "", fmt.Errorf("checksum authentication requires a local archive, not %T", localLocation)
+	}
+
+	data, err := ioutil.ReadFile(string(archive))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s for checksum verification: %s", archive, err)
+	}
+
+	got := sha256.Sum256(data)
+	if got != a.want {
+		return "", fmt.Errorf("checksum mismatch: expected %x, got %x", a.want, got)
+	}
+	return "checksum verified", nil
+}
+
+// KeyExpiryMode selects how a signatureAuthentication check should treat a
+// signature made by a key that has since expired. Provider publishers
+// occasionally let a signing key lapse without anything being wrong with
+// the releases it signed, so treating expiry as an unconditional hard
+// error would make otherwise-fine releases unverifiable forever.
+type KeyExpiryMode int
+
+const (
+	// KeyExpiryModeFail rejects a signature made by a key that has
+	// expired. This is the zero value, so expiry is enforced unless a
+	// caller opts into one of the other modes.
+	KeyExpiryModeFail KeyExpiryMode = iota
+
+	// KeyExpiryModeGracePeriod accepts a signature made by a key that
+	// expired within the configured grace period, and rejects it once
+	// that grace period has elapsed.
+	KeyExpiryModeGracePeriod
+
+	// KeyExpiryModeIgnore always accepts a signature regardless of key
+	// expiry.
+	KeyExpiryModeIgnore
+)
+
+// KeyExpiryPolicy controls how NewSignatureAuthentication treats a
+// signature made by a now-expired key. The zero value enforces expiry with
+// no grace period.
+type KeyExpiryPolicy struct {
+	Mode KeyExpiryMode
+
+	// GracePeriod is the extra time after a key's recorded expiry during
+	// which KeyExpiryModeGracePeriod still accepts it. It is ignored by
+	// the other modes.
+	GracePeriod time.Duration
+}
+
+// NewSignatureAuthentication returns a PackageAuthentication that checks a
+// detached OpenPGP signature of a document (typically a SHA256SUMS file)
+// against a trusted keyring, such as the one a provider's publisher makes
+// available alongside their releases, enforcing key expiry with no grace
+// period. Use NewSignatureAuthenticationWithExpiryPolicy to customize that.
+func NewSignatureAuthentication(signedDocument, signature []byte, armoredKeyring string) PackageAuthentication {
+	return NewSignatureAuthenticationWithExpiryPolicy(signedDocument, signature, armoredKeyring, KeyExpiryPolicy{})
+}
+
+// NewSignatureAuthenticationWithExpiryPolicy is like
+// NewSignatureAuthentication but lets the caller decide how strictly to
+// treat a signature made by a since-expired key, rather than always
+// failing.
+func NewSignatureAuthenticationWithExpiryPolicy(signedDocument, signature []byte, armoredKeyring string, expiryPolicy KeyExpiryPolicy) PackageAuthentication {
+	return signatureAuthentication{signedDocument, signature, armoredKeyring, expiryPolicy}
+}
+
+type signatureAuthentication struct {
+	signedDocument []byte
+	signature      []byte
+	armoredKeyring string
+	expiryPolicy   KeyExpiryPolicy
+}
+
+func (a signatureAuthentication) AuthenticatePackage(localLocation PackageLocation) (string, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(a.armoredKeyring))
+	if err != nil {
+		return "", fmt.Errorf("invalid signing keyring: %s", err)
+	}
+
+	signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(a.signedDocument), bytes.NewReader(a.signature))
+	if err != nil {
+		return "", fmt.Errorf("signature verification failed: %s", err)
+	}
+
+	desc := "signature verified"
+	for _, identity := range signer.Identities {
+		desc = fmt.Sprintf("signed by %s", identity.Name)
+		break
+	}
+
+	expiredSince, expired := keyExpiredSince(signer, time.Now())
+	if !expired {
+		return desc, nil
+	}
+	switch a.expiryPolicy.Mode {
+	case KeyExpiryModeIgnore:
+		return desc + " (signing key has expired)", nil
+	case KeyExpiryModeGracePeriod:
+		if expiredSince <= a.expiryPolicy.GracePeriod {
+			return desc + " (signing key has expired, within grace period)", nil
+		}
+		return "", fmt.Errorf("signing key expired %s ago, which is beyond the configured grace period of %s", expiredSince, a.expiryPolicy.GracePeriod)
+	default:
+		return "", fmt.Errorf("signing key has expired")
+	}
+}
+
+// keyExpiredSince reports how long ago signer's key expired, relative to
+// now, if it has an expiry time at all and that time is in the past.
+func keyExpiredSince(signer *openpgp.Entity, now time.Time) (time.Duration, bool) {
+	for _, identity := range signer.Identities {
+		sig := identity.SelfSignature
+		if sig == nil || sig.KeyLifetimeSecs == nil {
+			continue
+		}
+		expiry := sig.CreationTime.Add(time.Duration(*sig.KeyLifetimeSecs) * time.Second)
+		if now.After(expiry) {
+			return now.Sub(expiry), true
+		}
+	}
+	return 0, false
+}
+
+// newAnyKeySignatureAuthentication returns a PackageAuthentication that
+// succeeds if the signature can be verified against any one of the given
+// ASCII-armored keyrings, succeeding with that key's description. It
+// exists to support PackageMeta.Authentication, where a source may report
+// more than one trusted signing key for a provider.
+func newAnyKeySignatureAuthentication(signedDocument, signature []byte, armoredKeyrings []string, expiryPolicy KeyExpiryPolicy) PackageAuthentication {
+	return anyKeySignatureAuthentication{signedDocument, signature, armoredKeyrings, expiryPolicy}
+}
+
+type anyKeySignatureAuthentication struct {
+	signedDocument  []byte
+	signature       []byte
+	armoredKeyrings []string
+	expiryPolicy    KeyExpiryPolicy
+}
+
+func (a anyKeySignatureAuthentication) AuthenticatePackage(localLocation PackageLocation) (string, error) {
+	var lastErr error
+	for _, keyring := range a.armoredKeyrings {
+		desc, err := NewSignatureAuthenticationWithExpiryPolicy(a.signedDocument, a.signature, keyring, a.expiryPolicy).AuthenticatePackage(localLocation)
+		if err == nil {
+			return desc, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no signing keys were available to verify against")
+	}
+	return "", fmt.Errorf("signature did not match any trusted key: %s", lastErr)
+}
+
+// NewArchiveChecksumMatchAuthentication returns a PackageAuthentication
+// that checks that localLocation's own SHA256 sum matches the value
+// recorded against its filename in a downloaded SHA256SUMS-style document,
+// rather than against a single expected value. This is how a Source
+// authenticates a package using a registry's published checksums file
+// directly, without needing to have already parsed out the one line that
+// applies to this package.
+func NewArchiveChecksumMatchAuthentication(document []byte, filename string) PackageAuthentication {
+	return archiveChecksumMatchAuthentication{document, filename}
+}
+
+type archiveChecksumMatchAuthentication struct {
+	document []byte
+	filename string
+}
+
+func (a archiveChecksumMatchAuthentication) AuthenticatePackage(localLocation PackageLocation) (string, error) {
+	for _, line := range strings.Split(string(a.document), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[1] != a.filename {
+			continue
+		}
+		var want [sha256.Size]byte
+		if _, err := fmt.Sscanf(fields[0], "%x", &want); err != nil {
+			return "", fmt.Errorf("invalid checksum %q for %s in checksums document", fields[0], a.filename)
+		}
+		return NewChecksumAuthentication(want).AuthenticatePackage(localLocation)
+	}
+	return "", fmt.Errorf("checksums document does not include an entry for %s", a.filename)
+}
@@ -0,0 +1,38 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestFilesystemMirrorSourcePackageMetaSizeAndModTime(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "terraform-getproviders-stat")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	typeDir := filepath.Join(baseDir, "registry.terraform.io", "namespace0", "type0")
+	writeTestPackedArchive(t, typeDir, "terraform-provider-type0_1.0.0_linux_amd64.zip", map[string]string{
+		"terraform-provider-type0_v1.0.0_x5": "pretend binary",
+	})
+
+	source := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	meta, err := source.PackageMeta(provider, versionMust(t, "1.0.0"), Platform{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if meta.SizeBytes <= 0 {
+		t.Errorf("expected a positive SizeBytes, got %d", meta.SizeBytes)
+	}
+	if meta.ModTime.IsZero() {
+		t.Errorf("expected a non-zero ModTime")
+	}
+}
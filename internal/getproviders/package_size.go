@@ -0,0 +1,55 @@
+package getproviders
+
+import (
+	"net/http"
+	"os"
+)
+
+// PackageSize returns the on-disk or on-the-wire size in bytes of the
+// package described by meta, if its Location makes that determinable
+// without downloading the whole thing, and whether a size was found at
+// all.
+//
+// A PackageLocalArchive's size comes from stat'ing the archive file
+// directly. A PackageHTTPURL's size comes from the Content-Length header
+// of an HTTP HEAD request, using client, or http.DefaultClient if client is
+// nil; a server that omits Content-Length, or doesn't support HEAD,
+// reports no size rather than an error, since this is meant as a
+// best-effort hint for reports and UI, not something callers should need
+// to handle failing outright. PackageLocalDir and PackageFSArchive report
+// no size, since an unpacked directory's size on disk isn't meaningfully
+// comparable to another package's packed size.
+func PackageSize(client *http.Client, meta PackageMeta) (int64, bool, error) {
+	switch loc := meta.Location.(type) {
+	case PackageLocalArchive:
+		info, err := os.Stat(string(loc))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return 0, false, nil
+			}
+			return 0, false, err
+		}
+		return info.Size(), true, nil
+
+	case PackageHTTPURL:
+		if client == nil {
+			client = http.DefaultClient
+		}
+		req, err := http.NewRequest("HEAD", string(loc), nil)
+		if err != nil {
+			return 0, false, nil
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, false, nil
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.ContentLength < 0 {
+			return 0, false, nil
+		}
+		return resp.ContentLength, true, nil
+
+	default:
+		return 0, false, nil
+	}
+}
@@ -0,0 +1,96 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// PeerCacheSource is an experimental Source, intended for large CI fleets,
+// that first asks a set of peer runners on the same network whether they
+// already have a provider package cached before falling back to Fallback
+// (typically a RegistrySource or a network mirror) to fetch it the usual
+// way. Every package offered by a peer is checked against the SHA256 hash
+// that Fallback's own PackageMeta reports, so a stale or misbehaving peer
+// can only waste time, never cause an unverified package to be installed.
+//
+// This trades a little extra peer-to-peer chatter for a large reduction in
+// egress against the origin registry when many runners on the same network
+// are installing much the same set of providers at once.
+type PeerCacheSource struct {
+	// Fallback is consulted for version listings and package metadata, and
+	// is used directly whenever no peer has the requested package cached.
+	Fallback Source
+
+	// Peers are the base URLs of the peer caches to consult, such as
+	// "http://10.0.4.12:8430". Each is expected to serve
+	// "<peer>/providers/<hostname>/<namespace>/<type>/<version>/<os>_<arch>"
+	// with a "X-Provider-Package-SHA256" response header when it has a
+	// matching package cached.
+	Peers []string
+
+	httpClient *http.Client
+}
+
+// NewPeerCacheSource constructs a PeerCacheSource that tries peers before
+// falling back to fallback.
+func NewPeerCacheSource(fallback Source, peers []string) *PeerCacheSource {
+	return &PeerCacheSource{
+		Fallback:   fallback,
+		Peers:      peers,
+		httpClient: &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+var _ Source = (*PeerCacheSource)(nil)
+
+// AvailableVersions always defers to Fallback, because peers only serve
+// packages they already happen to have cached, not authoritative version
+// listings.
+func (s *PeerCacheSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	return s.Fallback.AvailableVersions(provider)
+}
+
+// PackageMeta asks Fallback for the authoritative package metadata, then
+// swaps in a peer's location for the package if a peer has a hash-verified
+// copy cached.
+func (s *PeerCacheSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	meta, err := s.Fallback.PackageMeta(provider, version, target)
+	if err != nil {
+		return PackageMeta{}, err
+	}
+	if peerLocation, ok := s.findOnPeer(provider, version, target, meta.SHA256Sum); ok {
+		meta.Location = peerLocation
+	}
+	return meta, nil
+}
+
+func (s *PeerCacheSource) findOnPeer(provider addrs.Provider, version Version, target Platform, wantSHA256 [sha256.Size]byte) (PackageLocation, bool) {
+	wantHash := hex.EncodeToString(wantSHA256[:])
+	for _, peer := range s.Peers {
+		peerURL := fmt.Sprintf(
+			"%s/providers/%s/%s/%s/%s/%s_%s",
+			strings.TrimSuffix(peer, "/"), provider.Hostname, provider.Namespace, provider.Type, version, target.OS, target.Arch,
+		)
+		resp, err := s.httpClient.Head(peerURL)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			continue
+		}
+		if !strings.EqualFold(resp.Header.Get("X-Provider-Package-SHA256"), wantHash) {
+			// This peer has something at that path, but it isn't the exact
+			// package we asked for, so we can't trust it.
+			continue
+		}
+		return PackageHTTPURL(peerURL), true
+	}
+	return nil, false
+}
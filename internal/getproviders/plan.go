@@ -0,0 +1,201 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// PlanEntry is the structured, pre-download description of what
+// ResolvePackages or ResolveBatch decided for a single provider: which
+// version it selected, where it would fetch the package from, and what
+// hash that package is expected to have.
+//
+// This exists so that the decisions an installer is about to act on can be
+// inspected and approved or rejected by an external policy engine (an OPA
+// or Sentinel policy, for example) before anything is downloaded.
+type PlanEntry struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version,omitempty"`
+	Platform string `json:"platform,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+
+	// Hashes carries every hash BuildPlan was able to record for this
+	// package, across both the zh: (zip) and h1: (unpacked directory)
+	// schemes documented in hash.go, in addition to the single zh: hash
+	// Hash always carries for backward compatibility. VerifyInstalledAgainstPlan
+	// uses this to recognize a package that was resolved from a source
+	// that only ever yields an h1: hash, such as an unpacked filesystem
+	// mirror.
+	Hashes []string `json:"hashes,omitempty"`
+
+	Error string `json:"error,omitempty"`
+
+	// Retryable and SuggestedAction are hints attached to a failed
+	// resolution, populated from the kind of error BuildPlan recorded, so
+	// that an orchestration system consuming the plan as a JSON error event
+	// can decide whether to self-heal -- retrying against a different
+	// network path, for example -- without having to pattern-match Error's
+	// free-form text. Both are left unset for a failure BuildPlan doesn't
+	// have a specific hint for.
+	Retryable       bool   `json:"retryable,omitempty"`
+	SuggestedAction string `json:"suggestedAction,omitempty"`
+}
+
+// BuildPlan converts a set of ResolutionResult values, such as those
+// returned by ResolvePackages, into PlanEntry values describing the same
+// decisions in a form that's easy to serialize and hand to a policy engine.
+//
+// A result that failed to resolve is still represented as a PlanEntry, with
+// Error set and the rest of the fields left at their zero values, so that a
+// policy engine can see -- and potentially tolerate -- resolution failures
+// alongside successes rather than the caller needing to filter them out
+// beforehand.
+func BuildPlan(results []ResolutionResult) []PlanEntry {
+	plan := make([]PlanEntry, len(results))
+	for i, result := range results {
+		entry := PlanEntry{Provider: result.Provider.String()}
+		if result.Err != nil {
+			entry.Error = result.Err.Error()
+			entry.Retryable, entry.SuggestedAction = retryHintForError(result.Err)
+			plan[i] = entry
+			continue
+		}
+		entry.Version = result.Version.String()
+		entry.Platform = result.Meta.TargetPlatform.String()
+		entry.Filename = result.Meta.Filename
+		entry.Hash = fmt.Sprintf("%s%x", HashSchemeZip, result.Meta.SHA256Sum)
+		if result.Meta.SHA256Sum != ([sha256.Size]byte{}) {
+			entry.Hashes = append(entry.Hashes, entry.Hash)
+		}
+		entry.Hashes = append(entry.Hashes, result.Meta.Hashes...)
+		plan[i] = entry
+	}
+	return plan
+}
+
+// LockFileFromPlan builds a LockFile that pins every provider in plan to
+// exactly the version and hash BuildPlan recorded for it, rather than a
+// version constraint an installer would have to re-resolve.
+//
+// This lets `terraform apply <planfile>` on a fresh runner -- one that
+// never ran `terraform init` against the configuration that produced the
+// plan -- install precisely the providers the plan was created with,
+// nothing else, by feeding the result straight to the same lock-file-aware
+// installation path a normal init would use.
+//
+// It returns an error if any entry in plan recorded a resolution failure
+// (Error set) or has no hash to pin, since neither can be installed
+// exactly.
+func LockFileFromPlan(plan []PlanEntry) (*LockFile, error) {
+	lock := &LockFile{Providers: make(map[addrs.Provider]LockedProvider, len(plan))}
+	for _, entry := range plan {
+		if entry.Error != "" {
+			return nil, fmt.Errorf("plan recorded a failed resolution for %s: %s", entry.Provider, entry.Error)
+		}
+		if entry.Hash == "" {
+			return nil, fmt.Errorf("plan entry for %s has no recorded hash to pin", entry.Provider)
+		}
+
+		provider, diags := addrs.ParseProviderSourceString(entry.Provider)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("invalid provider address %q in plan: %s", entry.Provider, diags.Err())
+		}
+		version, err := ParseVersion(entry.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q for %s in plan: %s", entry.Version, entry.Provider, err)
+		}
+
+		lock.Providers[provider] = LockedProvider{
+			Version: version,
+			Hashes:  []string{entry.Hash},
+		}
+	}
+	return lock, nil
+}
+
+// MarshalPlanJSON renders a resolution plan as indented JSON, suitable for
+// writing to a file or pipe for an external policy engine to consume.
+func MarshalPlanJSON(plan []PlanEntry) ([]byte, error) {
+	return json.MarshalIndent(plan, "", "  ")
+}
+
+// VerifyInstalledAgainstPlan checks that every provider in installed that
+// was also part of plan still has the same package contents it had when
+// plan was built, by comparing a fresh HashSchemeDir ("h1:") hash of each
+// installed package's unpacked directory against the hashes BuildPlan
+// recorded for it.
+//
+// This closes a gap that re-verifying a freshly downloaded package against
+// its recorded hash does not: an apply run on a machine that already has a
+// provider cached for the right version, installed from somewhere other
+// than where the plan was resolved, would otherwise be used as-is with no
+// check that it's the same package the plan was built against. A provider
+// in installed with no corresponding entry in plan, or a plan entry with no
+// recorded hash, is not an error here; it's simply not something this
+// function has anything to say about.
+func VerifyInstalledAgainstPlan(plan []PlanEntry, installed []Installed) error {
+	byKey := make(map[string]PlanEntry, len(plan))
+	for _, entry := range plan {
+		if entry.Error != "" {
+			continue
+		}
+		byKey[entry.Provider+"@"+entry.Version] = entry
+	}
+
+	for _, inst := range installed {
+		entry, ok := byKey[inst.Provider.String()+"@"+inst.Version.String()]
+		if !ok || len(entry.Hashes) == 0 {
+			continue
+		}
+
+		hash, err := PackageDirHash(inst.PackageDir)
+		if err != nil {
+			return fmt.Errorf("failed to verify %s %s against the plan: %s", inst.Provider, inst.Version, err)
+		}
+		if !hashInPlanEntry(hash, entry) {
+			return fmt.Errorf(
+				"provider %s %s installed for apply does not match the package the plan was built from; re-run the plan against this environment before applying it",
+				inst.Provider, inst.Version,
+			)
+		}
+	}
+	return nil
+}
+
+func hashInPlanEntry(hash string, entry PlanEntry) bool {
+	for _, h := range entry.Hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// retryHintForError decides the Retryable and SuggestedAction hints
+// BuildPlan attaches to a failed PlanEntry, recognizing the specific error
+// types in errors.go that a different network path -- a mirror instead of
+// the public registry, for example -- could plausibly resolve: a
+// registry host that can't be reached at all, or one that's rejecting
+// requests because of a misconfigured proxy's own authentication
+// requirement rather than the provider's credentials being wrong.
+func retryHintForError(err error) (retryable bool, suggestedAction string) {
+	switch e := err.(type) {
+	case ErrHostUnreachable, ErrHostNoProviders:
+		return true, "use_mirror"
+	case ErrUnauthorized:
+		if !e.HaveCredentials {
+			// No credentials were sent at all, which is consistent with a
+			// transparent proxy demanding its own authentication before a
+			// request ever reaches the registry, rather than the registry
+			// itself rejecting a bad provider credential.
+			return true, "use_mirror"
+		}
+		return false, "check_credentials"
+	default:
+		return false, ""
+	}
+}
@@ -0,0 +1,172 @@
+package getproviders
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestLockFileFromPlan(t *testing.T) {
+	plan := []PlanEntry{
+		{
+			Provider: "registry.terraform.io/hashicorp/null",
+			Version:  "3.1.0",
+			Hash:     "zh:0123456789012345678901234567890123456789012345678901234567890123",
+		},
+	}
+
+	lock, err := LockFileFromPlan(plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	locked, ok := lock.Providers[provider]
+	if !ok {
+		t.Fatalf("lock file has no entry for %s", provider)
+	}
+	if locked.Version.String() != "3.1.0" {
+		t.Errorf("wrong version: got %s, want 3.1.0", locked.Version)
+	}
+	if len(locked.Hashes) != 1 || locked.Hashes[0] != plan[0].Hash {
+		t.Errorf("wrong hashes: got %v", locked.Hashes)
+	}
+}
+
+func TestLockFileFromPlanFailedResolution(t *testing.T) {
+	plan := []PlanEntry{
+		{Provider: "registry.terraform.io/hashicorp/null", Error: "no available version matches the given constraints"},
+	}
+
+	if _, err := LockFileFromPlan(plan); err == nil {
+		t.Fatalf("expected error for a plan with a failed resolution")
+	}
+}
+
+func TestLockFileFromPlanMissingHash(t *testing.T) {
+	plan := []PlanEntry{
+		{Provider: "registry.terraform.io/hashicorp/null", Version: "3.1.0"},
+	}
+
+	if _, err := LockFileFromPlan(plan); err == nil {
+		t.Fatalf("expected error for a plan entry with no hash")
+	}
+}
+
+func TestVerifyInstalledAgainstPlan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-verify-installed")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "terraform-provider-null"), []byte("fake binary"), 0755); err != nil {
+		t.Fatalf("failed to write fake binary: %s", err)
+	}
+	hash, err := PackageDirHash(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	plan := []PlanEntry{
+		{Provider: provider.String(), Version: "3.1.0", Hashes: []string{hash}},
+	}
+	installed := []Installed{
+		{Provider: provider, Version: versionMust(t, "3.1.0"), PackageDir: dir},
+	}
+
+	if err := VerifyInstalledAgainstPlan(plan, installed); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestVerifyInstalledAgainstPlanMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-verify-installed")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "terraform-provider-null"), []byte("a different binary"), 0755); err != nil {
+		t.Fatalf("failed to write fake binary: %s", err)
+	}
+
+	provider := addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null")
+	plan := []PlanEntry{
+		{Provider: provider.String(), Version: "3.1.0", Hashes: []string{"h1:0000000000000000000000000000000000000000000000000000000000000000"}},
+	}
+	installed := []Installed{
+		{Provider: provider, Version: versionMust(t, "3.1.0"), PackageDir: dir},
+	}
+
+	if err := VerifyInstalledAgainstPlan(plan, installed); err == nil {
+		t.Fatalf("expected error for a mismatched installed package")
+	}
+}
+
+func TestBuildPlanRetryHints(t *testing.T) {
+	results := []ResolutionResult{
+		{
+			Provider: addrs.NewProvider(DefaultRegistryHost, "hashicorp", "null"),
+			Err:      ErrHostUnreachable{Hostname: svchost.Hostname("registry.terraform.io"), Wrapped: errors.New("connection refused")},
+		},
+		{
+			Provider: addrs.NewProvider(DefaultRegistryHost, "hashicorp", "aws"),
+			Err:      ErrUnauthorized{Hostname: svchost.Hostname("registry.terraform.io"), HaveCredentials: true},
+		},
+		{
+			Provider: addrs.NewProvider(DefaultRegistryHost, "hashicorp", "azurerm"),
+			Err:      ErrProviderNotKnown{},
+		},
+	}
+
+	plan := BuildPlan(results)
+
+	if !plan[0].Retryable || plan[0].SuggestedAction != "use_mirror" {
+		t.Errorf("wrong hint for unreachable host: retryable=%v action=%q", plan[0].Retryable, plan[0].SuggestedAction)
+	}
+	if plan[1].Retryable || plan[1].SuggestedAction != "check_credentials" {
+		t.Errorf("wrong hint for bad credentials: retryable=%v action=%q", plan[1].Retryable, plan[1].SuggestedAction)
+	}
+	if plan[2].Retryable || plan[2].SuggestedAction != "" {
+		t.Errorf("wrong hint for provider not known: retryable=%v action=%q", plan[2].Retryable, plan[2].SuggestedAction)
+	}
+}
+
+func TestPlanLockFileUpgradesNoSuitableVersion(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	source := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	constraints, err := ParseVersionConstraints(">= 5.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	reqs := []LockFileUpgradeRequest{
+		{Provider: provider, Constraints: constraints, Platforms: []Platform{{OS: "linux", Arch: "amd64"}}},
+	}
+
+	_, _, err = PlanLockFileUpgrades(source, NewLockFile(), reqs)
+	if _, ok := err.(ErrNoSuitableVersion); !ok {
+		t.Fatalf("wrong error type %T, want ErrNoSuitableVersion", err)
+	}
+}
+
+func versionMust(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := ParseVersion(s)
+	if err != nil {
+		t.Fatalf("invalid version %q: %s", s, err)
+	}
+	return v
+}
@@ -0,0 +1,28 @@
+package getproviders
+
+import (
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// PlatformCoverageSource is an optional extension to Source for sources
+// that can report which platforms a specific provider version supports
+// without a caller needing to call PackageMeta once per candidate platform
+// just to find out which ones fail.
+//
+// A registry's version listing response already includes each version's
+// supported platforms alongside its version string, so RegistrySource can
+// answer this with a single version listing request, made on each call; a
+// source with no
+// equivalent listing, such as one backed by a single package URL, has no
+// way to support it at all.
+type PlatformCoverageSource interface {
+	Source
+
+	// PlatformsForVersion returns the platforms known to be supported by
+	// the given provider version. Callers should first have established
+	// that version is valid for provider via AvailableVersions; passing a
+	// version the source doesn't know about returns a nil, rather than an
+	// error, since "no known platforms" and "unrecognized version" aren't
+	// distinguishable through this interface.
+	PlatformsForVersion(provider addrs.Provider, version Version) ([]Platform, error)
+}
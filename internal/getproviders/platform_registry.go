@@ -0,0 +1,69 @@
+package getproviders
+
+// WellKnownPlatforms is the authoritative set of target platforms that
+// Terraform and the wider provider ecosystem build official releases for.
+//
+// This list exists so that mirror tooling and installation logic have a
+// single shared understanding of which platforms to expect packages for,
+// separate from PlatformRegistry's support for accepting additional niche
+// platforms at runtime.
+var WellKnownPlatforms = []Platform{
+	{OS: "darwin", Arch: "amd64"},
+	{OS: "darwin", Arch: "arm64"},
+	{OS: "freebsd", Arch: "386"},
+	{OS: "freebsd", Arch: "amd64"},
+	{OS: "freebsd", Arch: "arm"},
+	{OS: "linux", Arch: "386"},
+	{OS: "linux", Arch: "amd64"},
+	{OS: "linux", Arch: "arm"},
+	{OS: "linux", Arch: "arm64"},
+	{OS: "openbsd", Arch: "386"},
+	{OS: "openbsd", Arch: "amd64"},
+	{OS: "solaris", Arch: "amd64"},
+	{OS: "windows", Arch: "386"},
+	{OS: "windows", Arch: "amd64"},
+	{OS: "windows", Arch: "arm64"},
+}
+
+// PlatformRegistry tracks which Platform values are considered valid,
+// starting from WellKnownPlatforms and allowing configuration to register
+// additional niche platforms -- such as linux_riscv64 -- that an
+// organization needs to mirror or install providers for, without needing a
+// Terraform code change to recognize them.
+type PlatformRegistry struct {
+	accepted map[Platform]bool
+}
+
+// NewPlatformRegistry constructs a PlatformRegistry pre-populated with
+// WellKnownPlatforms.
+func NewPlatformRegistry() *PlatformRegistry {
+	r := &PlatformRegistry{
+		accepted: make(map[Platform]bool, len(WellKnownPlatforms)),
+	}
+	for _, p := range WellKnownPlatforms {
+		r.accepted[p] = true
+	}
+	return r
+}
+
+// RegisterCustomPlatform adds p to the set of platforms the registry
+// accepts.
+func (r *PlatformRegistry) RegisterCustomPlatform(p Platform) {
+	r.accepted[p] = true
+}
+
+// IsKnown returns whether p is either one of WellKnownPlatforms or one
+// previously registered with RegisterCustomPlatform.
+func (r *PlatformRegistry) IsKnown(p Platform) bool {
+	return r.accepted[p]
+}
+
+// Known returns every platform the registry currently accepts, in no
+// particular order.
+func (r *PlatformRegistry) Known() []Platform {
+	ret := make([]Platform, 0, len(r.accepted))
+	for p := range r.accepted {
+		ret = append(ret, p)
+	}
+	return ret
+}
@@ -0,0 +1,130 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// pluginManifestFilename is the name of an optional file a provider
+// distribution package can include, either in the unpacked directory or
+// at the root of the zip for the packed layout, recording which plugin
+// protocol versions its binary speaks. Its presence lets callers like
+// FilesystemMirrorSource populate PackageMeta.ProtocolVersions without
+// having to execute the provider binary.
+const pluginManifestFilename = "terraform-plugin-manifest.json"
+
+// pluginManifestJSON is the on-disk representation of
+// pluginManifestFilename.
+type pluginManifestJSON struct {
+	ProtocolVersions []string `json:"protocolVersions"`
+}
+
+// legacyProtocolVersionFilename matches the "_x<N>" suffix that older
+// provider releases encoded directly in their binary filename (as in
+// terraform-provider-null_v2.1.2_x4), from back before providers reported
+// their protocol version over their own handshake. It's consulted only as
+// a last resort, when no manifest is present.
+var legacyProtocolVersionFilename = regexp.MustCompile(`_x(\d+)$`)
+
+// protocolVersionsFromUnpackedDir returns the plugin protocol versions
+// recorded for the package unpacked into dir, read from
+// pluginManifestFilename if present there, or else guessed from the
+// naming convention of whichever file in dir looks like the provider's
+// executable.
+//
+// It returns a nil VersionList, with no error, if neither source yields an
+// answer; callers should treat that the same as "unknown" rather than as a
+// failure, since older provider packages predate both conventions.
+func protocolVersionsFromUnpackedDir(dir string) (VersionList, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, pluginManifestFilename))
+	if err == nil {
+		return parsePluginManifest(data)
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %s", pluginManifestFilename, err)
+	}
+
+	entries, err := readDirIfExists(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return protocolVersionsFromBinaryNames(names), nil
+}
+
+// protocolVersionsFromZip is the packed-layout counterpart to
+// protocolVersionsFromUnpackedDir, reading the same manifest file or
+// guessing from the same naming convention, but from the entries of the
+// zip archive at zipPath rather than from a directory on disk.
+func protocolVersionsFromZip(zipPath string) (VersionList, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %s", zipPath, err)
+	}
+	defer r.Close()
+
+	names := make([]string, len(r.File))
+	for i, f := range r.File {
+		names[i] = f.Name
+		if f.Name != pluginManifestFilename {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from %s: %s", pluginManifestFilename, zipPath, err)
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from %s: %s", pluginManifestFilename, zipPath, err)
+		}
+		return parsePluginManifest(data)
+	}
+
+	return protocolVersionsFromBinaryNames(names), nil
+}
+
+func parsePluginManifest(data []byte) (VersionList, error) {
+	var raw pluginManifestJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", pluginManifestFilename, err)
+	}
+	versions := make(VersionList, 0, len(raw.ProtocolVersions))
+	for _, str := range raw.ProtocolVersions {
+		v, err := ParseVersion(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid protocol version %q in %s: %s", str, pluginManifestFilename, err)
+		}
+		versions = append(versions, v)
+	}
+	versions.Sort()
+	return versions, nil
+}
+
+// protocolVersionsFromBinaryNames applies legacyProtocolVersionFilename to
+// each of names, returning the single protocol version it finds encoded
+// there, if any. It returns nil if none of the names match, which is the
+// expected outcome for any provider built since protocol versions started
+// being negotiated over the plugin handshake instead.
+func protocolVersionsFromBinaryNames(names []string) VersionList {
+	for _, name := range names {
+		m := legacyProtocolVersionFilename.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		v, err := ParseVersion(m[1])
+		if err != nil {
+			continue
+		}
+		return VersionList{v}
+	}
+	return nil
+}
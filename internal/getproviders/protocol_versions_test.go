@@ -0,0 +1,88 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProtocolVersionsFromUnpackedDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-protocol-versions")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := `{"protocolVersions": ["5.0", "6.0"]}`
+	if err := ioutil.WriteFile(filepath.Join(dir, pluginManifestFilename), []byte(manifest), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %s", err)
+	}
+
+	got, err := protocolVersionsFromUnpackedDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("wrong number of versions: got %d, want 2", len(got))
+	}
+}
+
+func TestProtocolVersionsFromUnpackedDirLegacyFilename(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-protocol-versions")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	binPath := filepath.Join(dir, "terraform-provider-test_v1.2.3_x4")
+	if err := ioutil.WriteFile(binPath, []byte("fake binary"), 0755); err != nil {
+		t.Fatalf("failed to write fake binary: %s", err)
+	}
+
+	got, err := protocolVersionsFromUnpackedDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("wrong number of versions: got %d, want 1", len(got))
+	}
+	if got[0].String() != "4.0.0" {
+		t.Errorf("wrong version: got %s, want 4.0.0", got[0].String())
+	}
+}
+
+func TestProtocolVersionsFromZip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-protocol-versions")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	zipPath := filepath.Join(dir, "package.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("failed to create zip: %s", err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create(pluginManifestFilename)
+	if err != nil {
+		t.Fatalf("failed to create zip entry: %s", err)
+	}
+	if _, err := w.Write([]byte(`{"protocolVersions": ["5.0"]}`)); err != nil {
+		t.Fatalf("failed to write zip entry: %s", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %s", err)
+	}
+	f.Close()
+
+	got, err := protocolVersionsFromZip(zipPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("wrong number of versions: got %d, want 1", len(got))
+	}
+}
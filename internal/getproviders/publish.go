@@ -0,0 +1,194 @@
+package getproviders
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// PublishArtifact describes one platform's build output that is ready to
+// be packaged as part of a provider release.
+type PublishArtifact struct {
+	Platform   Platform
+	BinaryPath string
+}
+
+// PublishedPackage describes one of the zip files produced by
+// PublishRelease.
+type PublishedPackage struct {
+	Platform  Platform
+	Filename  string
+	SHA256Sum [sha256.Size]byte
+}
+
+// PublishRelease packages each of the given artifacts into a
+// terraform-provider-<type>_<version>_<os>_<arch>.zip file in outDir,
+// writes a terraform-provider-<type>_<version>_SHA256SUMS file summarizing
+// their hashes, and, if armoredSigningKey is non-empty, a detached
+// ASCII-armored signature of that file alongside it.
+//
+// This produces the same artifact layout the public registry expects, so
+// the result can be served directly by an HTTP or filesystem mirror. It is
+// intended for use by internal provider publishing pipelines, not as part
+// of normal Terraform operation.
+func PublishRelease(provider addrs.Provider, version Version, artifacts []PublishArtifact, outDir, armoredSigningKey string) ([]PublishedPackage, error) {
+	packages := make([]PublishedPackage, 0, len(artifacts))
+	var sums strings.Builder
+
+	for _, a := range artifacts {
+		filename := fmt.Sprintf("terraform-provider-%s_%s_%s.zip", provider.Type, version.String(), a.Platform.String())
+		zipPath := filepath.Join(outDir, filename)
+
+		sum, err := zipBinary(a.BinaryPath, zipPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to package %s: %s", a.Platform, err)
+		}
+
+		fmt.Fprintf(&sums, "%x  %s\n", sum, filename)
+		packages = append(packages, PublishedPackage{Platform: a.Platform, Filename: filename, SHA256Sum: sum})
+	}
+
+	sumsFilename := fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS", provider.Type, version.String())
+	sumsPath := filepath.Join(outDir, sumsFilename)
+	if err := ioutil.WriteFile(sumsPath, []byte(sums.String()), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %s", sumsPath, err)
+	}
+
+	if armoredSigningKey != "" {
+		if err := signFile(sumsPath, sumsPath+".sig", armoredSigningKey); err != nil {
+			return nil, fmt.Errorf("failed to sign %s: %s", sumsPath, err)
+		}
+	}
+
+	return packages, nil
+}
+
+// zipBinary compresses the executable at binaryPath into a new zip archive
+// at zipPath containing a single entry with the same base name, and
+// returns the SHA256 hash of the resulting archive.
+func zipBinary(binaryPath, zipPath string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+
+	in, err := os.Open(binaryPath)
+	if err != nil {
+		return sum, err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return sum, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return sum, err
+	}
+	hdr.Name = filepath.Base(binaryPath)
+	hdr.Method = zip.Deflate
+	w, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return sum, err
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return sum, err
+	}
+	if err := zw.Close(); err != nil {
+		return sum, err
+	}
+
+	if err := ioutil.WriteFile(zipPath, buf.Bytes(), 0644); err != nil {
+		return sum, err
+	}
+	sum = sha256.Sum256(buf.Bytes())
+	return sum, nil
+}
+
+// signFile produces a detached ASCII-armored OpenPGP signature of the file
+// at path, using the first private key found in armoredSigningKey, and
+// writes it to sigPath.
+func signFile(path, sigPath, armoredSigningKey string) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredSigningKey))
+	if err != nil {
+		return fmt.Errorf("invalid signing key: %s", err)
+	}
+	if len(keyring) == 0 {
+		return fmt.Errorf("signing key does not contain any keys")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	out, err := os.Create(sigPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	return openpgp.ArmoredDetachSign(out, keyring[0], f, nil)
+}
+
+// MirrorVersionsDocument is the JSON document served in response to the
+// network mirror protocol's "list available versions" request.
+type MirrorVersionsDocument struct {
+	Versions map[string]struct{} `json:"versions"`
+}
+
+// MirrorVersionDocument is the JSON document served in response to the
+// network mirror protocol's "list available installation packages for a
+// specific version" request.
+type MirrorVersionDocument struct {
+	Archives map[string]MirrorArchive `json:"archives"`
+}
+
+// MirrorArchive is a single entry in a MirrorVersionDocument, describing
+// where to download the package for one platform and how to verify it.
+type MirrorArchive struct {
+	URL    string   `json:"url"`
+	Hashes []string `json:"hashes,omitempty"`
+}
+
+// MirrorIndexFragments builds the JSON fragments that a network mirror
+// needs to serve for a single published provider version: the versions
+// index, and the per-version archive listing referencing the packages
+// produced by PublishRelease at the given base URL.
+func MirrorIndexFragments(version Version, packages []PublishedPackage, baseURL string) (versions, archives []byte, err error) {
+	versionsDoc := MirrorVersionsDocument{
+		Versions: map[string]struct{}{version.String(): {}},
+	}
+	versions, err = json.MarshalIndent(versionsDoc, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archivesDoc := MirrorVersionDocument{
+		Archives: make(map[string]MirrorArchive, len(packages)),
+	}
+	for _, p := range packages {
+		archivesDoc.Archives[p.Platform.String()] = MirrorArchive{
+			URL:    strings.TrimSuffix(baseURL, "/") + "/" + p.Filename,
+			Hashes: []string{fmt.Sprintf("%s%x", HashSchemeZip, p.SHA256Sum)},
+		}
+	}
+	archives, err = json.MarshalIndent(archivesDoc, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return versions, archives, nil
+}
@@ -0,0 +1,128 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// QuarantineRecord captures why a downloaded package was quarantined, for
+// writing alongside it so that whoever investigates later doesn't need to
+// separately correlate it with installer logs.
+type QuarantineRecord struct {
+	Provider string
+	Version  string
+
+	// Source describes where the package was downloaded from, such as a
+	// registry or mirror URL, for tracing a suspicious artifact back to
+	// where it came from.
+	Source string
+
+	// ExpectedHashes lists the hash strings the package was expected to
+	// match, in the same Hashes format used by LockedProvider.
+	ExpectedHashes []string
+
+	// ActualSHA256 is the hex-encoded SHA256 sum actually computed for the
+	// downloaded package.
+	ActualSHA256 string
+
+	// Reason is a human-readable description of what verification check
+	// failed and why.
+	Reason string
+
+	QuarantinedAt time.Time
+}
+
+// QuarantinePackage moves the downloaded package at localPath -- either a
+// zip archive file or an already-unpacked directory -- into quarantineDir
+// along with a JSON metadata file describing why it was quarantined,
+// rather than deleting it outright, so that a security team can retrieve
+// and analyze a suspicious artifact instead of it being lost the moment
+// verification fails.
+//
+// localPath no longer exists after a successful call: it is moved, not
+// copied, falling back to a recursive copy-then-remove if quarantineDir is
+// on a different filesystem. The returned string is the path the package
+// was moved to.
+func QuarantinePackage(quarantineDir string, localPath string, record QuarantineRecord) (string, error) {
+	if err := os.MkdirAll(quarantineDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory %s: %s", quarantineDir, err)
+	}
+
+	record.QuarantinedAt = time.Now()
+	destName := fmt.Sprintf("%s-%d", filepath.Base(localPath), record.QuarantinedAt.UnixNano())
+	destPath := filepath.Join(quarantineDir, destName)
+
+	if err := movePath(localPath, destPath); err != nil {
+		return "", fmt.Errorf("failed to move %s into quarantine: %s", localPath, err)
+	}
+
+	metadata, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return destPath, fmt.Errorf("quarantined %s but failed to encode metadata: %s", destPath, err)
+	}
+	metadataPath := destPath + ".json"
+	if err := ioutil.WriteFile(metadataPath, metadata, 0600); err != nil {
+		return destPath, fmt.Errorf("quarantined %s but failed to write metadata to %s: %s", destPath, metadataPath, err)
+	}
+
+	return destPath, nil
+}
+
+// movePath moves src to dest, which may each be either a regular file or a
+// directory, preferring a plain rename and falling back to a recursive
+// copy-then-remove when the rename fails, e.g. because src and dest are on
+// different filesystems.
+func movePath(src, dest string) error {
+	if err := os.Rename(src, dest); err == nil {
+		return nil
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if err := copyDirContents(src, dest); err != nil {
+			return err
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+			return err
+		}
+		if err := copyFileContents(src, dest); err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(src)
+}
+
+func copyDirContents(srcDir, destDir string) error {
+	if err := os.MkdirAll(destDir, 0700); err != nil {
+		return err
+	}
+
+	entries, err := readDirIfExists(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		srcPath := filepath.Join(srcDir, entry.Name())
+		destPath := filepath.Join(destDir, entry.Name())
+		if entry.IsDir() {
+			if err := copyDirContents(srcPath, destPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := copyFileContents(srcPath, destPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
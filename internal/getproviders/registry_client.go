@@ -1,6 +1,8 @@
 package getproviders
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -11,6 +13,7 @@ import (
 	"time"
 
 	"github.com/apparentlymart/go-versions/versions"
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	svchost "github.com/hashicorp/terraform-svchost"
 	svcauth "github.com/hashicorp/terraform-svchost/auth"
 
@@ -29,17 +32,145 @@ type registryClient struct {
 	creds   svcauth.HostCredentials
 
 	httpClient *http.Client
+
+	// transport is the same RoundTripper installed as httpClient's
+	// (unexported) inner transport, kept here so that trustAdditionalCA and
+	// useProxy can adjust its TLS and proxy settings in place after
+	// construction without needing to reach into httpclient's own internals.
+	transport *http.Transport
+
+	// lastVersionPlatforms records the platforms advertised for each
+	// version string seen in the most recent call to ProviderVersions, so
+	// that a subsequent PackageMeta call on the same client can report
+	// which platforms a version does support when the requested one isn't
+	// among them.
+	lastVersionPlatforms map[string][]Platform
 }
 
 func newRegistryClient(baseURL *url.URL, creds svcauth.HostCredentials) *registryClient {
-	httpClient := httpclient.New()
+	transport := cleanhttp.DefaultPooledTransport()
+	httpClient := httpclient.NewWithTransport(transport)
 	httpClient.Timeout = 10 * time.Second
 
 	return &registryClient{
 		baseURL:    baseURL,
 		creds:      creds,
 		httpClient: httpClient,
+		transport:  transport,
+	}
+}
+
+// trustAdditionalCA reconfigures the client to also trust the given
+// PEM-encoded CA certificate, in addition to whatever the system's normal
+// trust store already trusts, so that an operator can opt a corporate
+// TLS-intercepting proxy's CA in for provider traffic specifically rather
+// than installing it system-wide.
+func (c *registryClient) trustAdditionalCA(caCertPEM string) error {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(caCertPEM)) {
+		return fmt.Errorf("does not contain a valid PEM-encoded certificate")
+	}
+	c.transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return nil
+}
+
+// useProxy reconfigures the client to send its requests through the proxy
+// described by proxyURLStr, which may use the "http", "https", or "socks5"
+// scheme and may include a userinfo component ("user:password@host:port")
+// for proxies that require authentication. This is an alternative to the
+// usual HTTP_PROXY/HTTPS_PROXY/ALL_PROXY environment variables for
+// environments, such as some CI systems, that can't inject environment
+// variables into the Terraform process but can write its configuration.
+func (c *registryClient) useProxy(proxyURLStr string) error {
+	proxyURL, err := url.Parse(proxyURLStr)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %s", err)
 	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+		// okay
+	default:
+		return fmt.Errorf("unsupported proxy URL scheme %q (must be http, https, or socks5)", proxyURL.Scheme)
+	}
+	c.transport.Proxy = http.ProxyURL(proxyURL)
+	return nil
+}
+
+// recordTo wraps the client's transport so that every request it makes is
+// appended to recording, for the debug recording mode that lets a user
+// attach reproducible evidence to a bug report about a misbehaving private
+// registry.
+func (c *registryClient) recordTo(recording *DebugRecording) {
+	timeout := c.httpClient.Timeout
+	c.httpClient = httpclient.NewWithTransport(recording.Transport(c.transport))
+	c.httpClient.Timeout = timeout
+}
+
+// tlsErrorHint checks whether err looks like one of a couple of TLS
+// verification failures that have a more actionable explanation than Go's
+// own generic wording, and if so returns that explanation as a standalone
+// sentence to append alongside err's own message. It returns the empty
+// string for any error it doesn't recognize, and never alters err itself,
+// so a caller combining the two keeps err's original type and message
+// intact for errors.As/errors.Is and Unwrap.
+//
+// The two cases recognized are a TLS-intercepting proxy substituting its
+// own certificate for the real one, and a certificate that's invalid only
+// because the local system clock is skewed relative to the certificate's
+// validity window.
+func tlsErrorHint(err error) string {
+	var unknownAuth x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuth) {
+		return "this usually means traffic is passing through a TLS-intercepting proxy; " +
+			"if that's expected here, configure RegistrySource.TrustedProxyCACertPEM with the proxy's CA certificate"
+	}
+
+	var invalidCert x509.CertificateInvalidError
+	if errors.As(err, &invalidCert) && invalidCert.Reason == x509.Expired && invalidCert.Cert != nil {
+		if skew, desc, ok := certClockSkew(invalidCert.Cert, time.Now()); ok {
+			return fmt.Sprintf("the local system clock appears to be %s %s; check that it's set correctly", skew, desc)
+		}
+	}
+
+	return ""
+}
+
+// certClockSkew compares now against cert's validity window and, if now
+// falls outside it, returns the apparent magnitude of the local clock's
+// skew and whether that skew is "ahead" or "behind", on the assumption that
+// the certificate's own validity window is correct and it's the local
+// clock that's wrong. This is only a heuristic -- the certificate could
+// instead be genuinely expired, or not yet valid -- but it's a more useful
+// first guess than Go's own "certificate has expired or is not yet valid"
+// message on its own.
+func certClockSkew(cert *x509.Certificate, now time.Time) (skew time.Duration, desc string, ok bool) {
+	switch {
+	case now.Before(cert.NotBefore):
+		return cert.NotBefore.Sub(now), "behind", true
+	case now.After(cert.NotAfter):
+		return now.Sub(cert.NotAfter), "ahead", true
+	default:
+		return 0, "", false
+	}
+}
+
+// resolveRegistryURL resolves a possibly-relative URL string from a
+// registry response against the URL that response was actually served
+// from, the same way PackageMeta already does for a package's own download
+// URL, and rejects anything that doesn't end up as plain http or https.
+func resolveRegistryURL(resp *http.Response, raw string) (string, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	resolved := resp.Request.URL.ResolveReference(parsed)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return "", fmt.Errorf("must use http or https scheme")
+	}
+	return resolved.String(), nil
 }
 
 // ProviderVersions returns the raw version strings produced by the registry
@@ -99,7 +230,11 @@ func (c *registryClient) ProviderVersions(addr addrs.Provider) ([]string, error)
 	// should change compatibility only in new major versions.
 	type ResponseBody struct {
 		Versions []struct {
-			Version string `json:"version"`
+			Version   string `json:"version"`
+			Platforms []struct {
+				OS   string `json:"os"`
+				Arch string `json:"arch"`
+			} `json:"platforms"`
 		} `json:"versions"`
 	}
 	var body ResponseBody
@@ -109,6 +244,15 @@ func (c *registryClient) ProviderVersions(addr addrs.Provider) ([]string, error)
 		return nil, c.errQueryFailed(addr, err)
 	}
 
+	c.lastVersionPlatforms = make(map[string][]Platform, len(body.Versions))
+	for _, v := range body.Versions {
+		platforms := make([]Platform, len(v.Platforms))
+		for i, p := range v.Platforms {
+			platforms[i] = Platform{OS: p.OS, Arch: p.Arch}
+		}
+		c.lastVersionPlatforms[v.Version] = platforms
+	}
+
 	if len(body.Versions) == 0 {
 		return nil, nil
 	}
@@ -120,6 +264,14 @@ func (c *registryClient) ProviderVersions(addr addrs.Provider) ([]string, error)
 	return ret, nil
 }
 
+// PlatformsForVersion returns the platforms the registry advertised support
+// for the given raw version string, as most recently observed by a call to
+// ProviderVersions. It returns nil if ProviderVersions has not been called
+// yet or did not mention the given version.
+func (c *registryClient) PlatformsForVersion(versionStr string) []Platform {
+	return c.lastVersionPlatforms[versionStr]
+}
+
 // PackageMeta returns metadata about a distribution package for a
 // provider.
 //
@@ -172,14 +324,19 @@ func (c *registryClient) PackageMeta(provider addrs.Provider, version Version, t
 	}
 
 	type ResponseBody struct {
-		Protocols   []string `json:"protocols"`
-		OS          string   `json:"os"`
-		Arch        string   `json:"arch"`
-		Filename    string   `json:"filename"`
-		DownloadURL string   `json:"download_url"`
-		SHA256Sum   string   `json:"shasum"`
-
-		// TODO: Other metadata for signature checking
+		Protocols           []string `json:"protocols"`
+		OS                  string   `json:"os"`
+		Arch                string   `json:"arch"`
+		Filename            string   `json:"filename"`
+		DownloadURL         string   `json:"download_url"`
+		SHA256Sum           string   `json:"shasum"`
+		SHASumsURL          string   `json:"shasums_url"`
+		SHASumsSignatureURL string   `json:"shasums_signature_url"`
+		SigningKeys         struct {
+			GPGPublicKeys []struct {
+				ASCIIArmor string `json:"ascii_armor"`
+			} `json:"gpg_public_keys"`
+		} `json:"signing_keys"`
 	}
 	var body ResponseBody
 
@@ -221,6 +378,22 @@ func (c *registryClient) PackageMeta(provider addrs.Provider, version Version, t
 		// SHA256Sum is populated below
 	}
 
+	if body.SHASumsURL != "" {
+		if resolved, err := resolveRegistryURL(resp, body.SHASumsURL); err == nil {
+			ret.ChecksumsSHA256SumsURL = resolved
+		}
+	}
+	if body.SHASumsSignatureURL != "" {
+		if resolved, err := resolveRegistryURL(resp, body.SHASumsSignatureURL); err == nil {
+			ret.ChecksumsSignatureURL = resolved
+		}
+	}
+	for _, key := range body.SigningKeys.GPGPublicKeys {
+		if key.ASCIIArmor != "" {
+			ret.SigningKeys = append(ret.SigningKeys, key.ASCIIArmor)
+		}
+	}
+
 	if len(body.SHA256Sum) != len(ret.SHA256Sum)*2 {
 		return PackageMeta{}, c.errQueryFailed(
 			provider,
@@ -303,7 +476,15 @@ func (c *registryClient) addHeadersToRequest(req *http.Request) {
 	req.Header.Set(terraformVersionHeader, version.String())
 }
 
+// errQueryFailed wraps err as an ErrQueryFailed for the given provider,
+// preserving err's own type and message so that callers doing
+// errors.As/errors.Is against the result still see through to it. If err
+// looks like one of the TLS failures tlsErrorHint knows how to explain, the
+// explanation is appended as additional context rather than replacing err.
 func (c *registryClient) errQueryFailed(provider addrs.Provider, err error) error {
+	if hint := tlsErrorHint(err); hint != "" {
+		err = fmt.Errorf("%w (%s)", err, hint)
+	}
 	return ErrQueryFailed{
 		Provider: provider,
 		Wrapped:  err,
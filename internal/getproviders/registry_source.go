@@ -2,6 +2,7 @@ package getproviders
 
 import (
 	"fmt"
+	"sync"
 
 	svchost "github.com/hashicorp/terraform-svchost"
 	disco "github.com/hashicorp/terraform-svchost/disco"
@@ -13,9 +14,37 @@ import (
 // their originating provider registries.
 type RegistrySource struct {
 	services *disco.Disco
+
+	// TrustedProxyCACertPEM, if set, is an additional PEM-encoded CA
+	// certificate that TLS connections made while fetching provider
+	// versions and packages will trust, alongside the normal system trust
+	// store. This exists for corporate networks that run a TLS-intercepting
+	// proxy, so that an operator can explicitly opt that proxy's CA in for
+	// provider traffic specifically, rather than having to install it into
+	// the whole system's trust store.
+	TrustedProxyCACertPEM string
+
+	// ProxyURL, if set, routes all provider registry and download traffic
+	// through the given proxy instead of relying on the usual
+	// HTTP_PROXY/HTTPS_PROXY/ALL_PROXY environment variables. It accepts
+	// "http", "https", or "socks5" schemes, and may include a userinfo
+	// component to authenticate with the proxy. This exists for CI systems
+	// that can configure Terraform directly but cannot inject environment
+	// variables into the process that runs it.
+	ProxyURL string
+
+	// DebugRecording, if set, receives a redacted record of every HTTP
+	// exchange made against a provider registry through this source, for
+	// attaching as reproducible evidence to a bug report about a
+	// misbehaving private registry.
+	DebugRecording *DebugRecording
+
+	checksumsOnce  sync.Once
+	checksumsCache *ChecksumsCache
 }
 
 var _ Source = (*RegistrySource)(nil)
+var _ PlatformCoverageSource = (*RegistrySource)(nil)
 
 // NewRegistrySource creates and returns a new source that will install
 // providers from their originating provider registries.
@@ -87,7 +116,88 @@ func (s *RegistrySource) PackageMeta(provider addrs.Provider, version Version, t
 		return PackageMeta{}, err
 	}
 
-	return client.PackageMeta(provider, version, target)
+	meta, err := client.PackageMeta(provider, version, target)
+	if notSupported, ok := err.(ErrPlatformNotSupported); ok {
+		err = s.describePlatformSupport(client, notSupported)
+	}
+	return meta, err
+}
+
+// ChecksumsDocument returns the SHA256SUMS document and its detached
+// signature that meta (as returned by PackageMeta) advertised for
+// provider's release at version, downloading them if this is the first
+// call for that provider and version and reusing the result for every call
+// after that. A caller resolving several platforms of the same release in
+// the same run -- the ordinary case when locking or mirroring for more than
+// one target platform at once -- can therefore call this once per platform
+// without paying for a redundant download each time.
+//
+// It returns an error, without downloading anything, if meta didn't
+// advertise both URLs.
+func (s *RegistrySource) ChecksumsDocument(provider addrs.Provider, version Version, meta PackageMeta) (document, signature []byte, err error) {
+	if meta.ChecksumsSHA256SumsURL == "" || meta.ChecksumsSignatureURL == "" {
+		return nil, nil, fmt.Errorf("%s %s has no checksums document to fetch", provider, version)
+	}
+	s.checksumsOnce.Do(func() {
+		s.checksumsCache = NewChecksumsCache(nil)
+	})
+	return s.checksumsCache.Get(provider, version, meta.ChecksumsSHA256SumsURL, meta.ChecksumsSignatureURL)
+}
+
+// PlatformsForVersion implements PlatformCoverageSource by returning the
+// platforms the registry's version listing advertised for version,
+// querying that listing anew on every call; earlier results are not reused.
+func (s *RegistrySource) PlatformsForVersion(provider addrs.Provider, version Version) ([]Platform, error) {
+	client, err := s.registryClient(provider.Hostname)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.ProviderVersions(provider); err != nil {
+		return nil, err
+	}
+	return client.PlatformsForVersion(version.String()), nil
+}
+
+// describePlatformSupport enriches a "platform not supported" error with
+// the set of platforms the requested version does support, and the newest
+// version known to support the originally-requested platform, so that the
+// caller can give the user an actionable hint instead of a bare "not
+// available" error.
+//
+// Any problems encountered while gathering this extra context are ignored
+// and the original error is returned unenriched, since the caller already
+// has a valid error to report without it.
+func (s *RegistrySource) describePlatformSupport(client *registryClient, err ErrPlatformNotSupported) error {
+	versionStrs, qerr := client.ProviderVersions(err.Provider)
+	if qerr != nil {
+		return err
+	}
+
+	err.Available = client.PlatformsForVersion(err.Version.String())
+
+	var newest Version
+	haveNewest := false
+	for _, str := range versionStrs {
+		v, perr := ParseVersion(str)
+		if perr != nil {
+			continue
+		}
+		supportsTarget := false
+		for _, p := range client.PlatformsForVersion(str) {
+			if p == err.Platform {
+				supportsTarget = true
+				break
+			}
+		}
+		if supportsTarget && (!haveNewest || v.GreaterThan(newest)) {
+			newest = v
+			haveNewest = true
+		}
+	}
+	err.NewestSupportedVersion = newest
+	err.HaveNewestSupportedVersion = haveNewest
+
+	return err
 }
 
 // LookupLegacyProviderNamespace is a special method available only on
@@ -151,5 +261,19 @@ func (s *RegistrySource) registryClient(hostname svchost.Hostname) (*registryCli
 		return nil, fmt.Errorf("failed to retrieve credentials for %s: %s", hostname, err)
 	}
 
-	return newRegistryClient(url, creds), nil
+	client := newRegistryClient(url, creds)
+	if s.TrustedProxyCACertPEM != "" {
+		if err := client.trustAdditionalCA(s.TrustedProxyCACertPEM); err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy CA certificate: %s", err)
+		}
+	}
+	if s.ProxyURL != "" {
+		if err := client.useProxy(s.ProxyURL); err != nil {
+			return nil, fmt.Errorf("invalid proxy configuration: %s", err)
+		}
+	}
+	if s.DebugRecording != nil {
+		client.recordTo(s.DebugRecording)
+	}
+	return client, nil
 }
@@ -0,0 +1,170 @@
+package getproviders
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// ResolutionRequest describes a single provider to resolve as part of a
+// call to ResolvePackages.
+type ResolutionRequest struct {
+	Provider addrs.Provider
+
+	// Acceptable is called with each version the Source reports as
+	// available, in ascending precedence order, and should return true if
+	// the caller would be willing to install that version. ResolvePackages
+	// selects the newest version for which Acceptable returns true. A nil
+	// Acceptable accepts every version.
+	Acceptable func(Version) bool
+
+	// Target is the platform to request package metadata for, once a
+	// version has been selected.
+	Target Platform
+
+	// Overrides, if non-nil, is consulted before any version selection
+	// happens. If it has an override configured for Provider, that pinned
+	// version is used as-is -- bypassing Acceptable and the constraint it
+	// implements entirely -- and the resulting ResolutionResult carries the
+	// override's warning for the caller to surface to the user.
+	Overrides *Overrides
+
+	// Trace, if non-nil, is called once for every version ResolvePackages
+	// considers while resolving this request, in the same ascending
+	// precedence order the Source reported them, plus once more with the
+	// outcome of the package metadata lookup for the version it selects, if
+	// it selects one. A version pinned by Overrides is reported only through
+	// that final call. This is for a caller that wants to show a user
+	// exactly why resolution landed on the version it did -- or didn't
+	// land on the version they expected -- rather than only the final
+	// selection or error.
+	//
+	// Trace may be called concurrently with Trace calls for other
+	// requests passed to the same ResolvePackages call, but never
+	// concurrently with another Trace call for the same request.
+	Trace func(ResolutionTraceEvent)
+}
+
+// ResolutionTraceEvent describes the outcome for a single version
+// considered while resolving a ResolutionRequest, for use with its Trace
+// field.
+type ResolutionTraceEvent struct {
+	Version Version
+
+	// Accepted is true if this version passed the request's Acceptable
+	// function, or if this event instead describes the final selected
+	// version's package metadata lookup succeeding.
+	Accepted bool
+
+	// Reason is a short, human-oriented explanation of the outcome, such
+	// as "excluded by version constraint" or "selected: newest version
+	// satisfying constraints". It's meant for display in a trace log, not
+	// for programmatic matching.
+	Reason string
+}
+
+// ResolutionResult is the outcome of resolving a single ResolutionRequest.
+type ResolutionResult struct {
+	Provider addrs.Provider
+	Version  Version
+	Meta     PackageMeta
+	Err      error
+
+	// Warning is set when the request's Overrides pinned Provider to a
+	// specific version, and should be surfaced to the user alongside a
+	// successful result to discourage leaving the override in place longer
+	// than necessary.
+	Warning string
+}
+
+// ResolvePackages concurrently resolves each of the given requests against
+// source, running version listing, version selection, and package metadata
+// retrieval for each provider as an independent pipeline rather than
+// forcing every provider through the same phase before any of them can
+// proceed to the next.
+//
+// This cuts overall wall-clock time when resolving many providers against a
+// high-latency registry, since a slow provider no longer blocks the others
+// from making progress. Results are returned in the same order as reqs,
+// once every pipeline has either completed or failed.
+func ResolvePackages(source Source, reqs []ResolutionRequest) []ResolutionResult {
+	results := make([]ResolutionResult, len(reqs))
+
+	var wg sync.WaitGroup
+	wg.Add(len(reqs))
+	for i := range reqs {
+		i, req := i, reqs[i]
+		go func() {
+			defer wg.Done()
+			results[i] = resolveOne(source, req)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func resolveOne(source Source, req ResolutionRequest) ResolutionResult {
+	result := ResolutionResult{Provider: req.Provider}
+
+	if locked, warning, ok := req.Overrides.Override(req.Provider); ok {
+		meta, err := source.PackageMeta(req.Provider, locked.Version, req.Target)
+		if err != nil {
+			req.trace(locked.Version, false, fmt.Sprintf("rejected override: %s", err))
+			result.Err = err
+			return result
+		}
+		req.trace(locked.Version, true, "selected: pinned by local override file")
+		result.Version = locked.Version
+		result.Meta = meta
+		result.Warning = warning
+		return result
+	}
+
+	var selected Version
+	found := false
+	err := StreamAvailableVersions(source, req.Provider, func(v Version) bool {
+		if req.Acceptable == nil || req.Acceptable(v) {
+			selected = v
+			found = true
+			req.trace(v, true, "satisfies version constraint")
+		} else {
+			req.trace(v, false, "excluded by version constraint")
+		}
+		return true // keep consuming so we find the newest acceptable version
+	})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if !found {
+		result.Err = ErrNoSuitableVersion{Provider: req.Provider}
+		return result
+	}
+
+	meta, err := source.PackageMeta(req.Provider, selected, req.Target)
+	if err != nil {
+		req.trace(selected, false, fmt.Sprintf("rejected after selection: %s", err))
+		result.Err = err
+		return result
+	}
+
+	req.trace(selected, true, "selected: newest version satisfying constraints")
+	result.Version = selected
+	result.Meta = meta
+	return result
+}
+
+// trace calls req.Trace, if set, recording that version was considered with
+// the given outcome and reason.
+func (req ResolutionRequest) trace(version Version, accepted bool, reason string) {
+	if req.Trace == nil {
+		return
+	}
+	req.Trace(ResolutionTraceEvent{
+		Version:  version,
+		Accepted: accepted,
+		Reason:   reason,
+	})
+}
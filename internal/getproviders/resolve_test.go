@@ -0,0 +1,109 @@
+package getproviders
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestResolvePackagesTrace(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	source := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	var events []ResolutionTraceEvent
+	req := ResolutionRequest{
+		Provider: provider,
+		Acceptable: func(v Version) bool {
+			return v.String() == "0.0.0"
+		},
+		Target: Platform{OS: "linux", Arch: "amd64"},
+		Trace: func(event ResolutionTraceEvent) {
+			events = append(events, event)
+		},
+	}
+
+	results := ResolvePackages(source, []ResolutionRequest{req})
+	if len(results) != 1 {
+		t.Fatalf("wrong number of results: got %d, want 1", len(results))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("unexpected error: %s", results[0].Err)
+	}
+	if got, want := results[0].Version.String(), "0.0.0"; got != want {
+		t.Fatalf("wrong selected version: got %s, want %s", got, want)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("wrong number of trace events: got %d, want 3\n%#v", len(events), events)
+	}
+	if events[0].Version.String() != "0.0.0" || !events[0].Accepted {
+		t.Errorf("expected first event to accept 0.0.0: got %#v", events[0])
+	}
+	if events[1].Version.String() != "1.0.0" || events[1].Accepted {
+		t.Errorf("expected second event to reject 1.0.0: got %#v", events[1])
+	}
+	if events[2].Version.String() != "0.0.0" || !events[2].Accepted {
+		t.Errorf("expected final event to confirm the selection: got %#v", events[2])
+	}
+}
+
+func TestResolvePackagesNoSuitableVersion(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	source := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	req := ResolutionRequest{
+		Provider: provider,
+		Acceptable: func(v Version) bool {
+			return false // no version is ever acceptable
+		},
+		Target: Platform{OS: "linux", Arch: "amd64"},
+	}
+
+	results := ResolvePackages(source, []ResolutionRequest{req})
+	if len(results) != 1 {
+		t.Fatalf("wrong number of results: got %d, want 1", len(results))
+	}
+	if _, ok := results[0].Err.(ErrNoSuitableVersion); !ok {
+		t.Fatalf("wrong error type %T, want ErrNoSuitableVersion", results[0].Err)
+	}
+}
+
+func TestResolvePackagesOverride(t *testing.T) {
+	baseDir, close := testSyntheticMirror(t, 1, 1, 2)
+	defer close()
+
+	source := NewFilesystemMirrorSource(baseDir)
+	provider := addrs.NewProvider(DefaultRegistryHost, "namespace0", "type0")
+
+	overrides := NewOverrides()
+	overrides.Providers[provider] = LockedProvider{Version: versionMust(t, "0.0.0")}
+
+	req := ResolutionRequest{
+		Provider: provider,
+		Acceptable: func(v Version) bool {
+			return v.String() == "1.0.0" // would select 1.0.0 if the override didn't take precedence
+		},
+		Target:    Platform{OS: "linux", Arch: "amd64"},
+		Overrides: overrides,
+	}
+
+	results := ResolvePackages(source, []ResolutionRequest{req})
+	if len(results) != 1 {
+		t.Fatalf("wrong number of results: got %d, want 1", len(results))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("unexpected error: %s", results[0].Err)
+	}
+	if got, want := results[0].Version.String(), "0.0.0"; got != want {
+		t.Fatalf("wrong selected version: got %s, want %s (override should take precedence over Acceptable)", got, want)
+	}
+	if results[0].Warning == "" {
+		t.Errorf("expected a non-empty warning for an overridden provider")
+	}
+}
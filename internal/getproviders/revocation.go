@@ -0,0 +1,99 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// Revocation is a single entry in a RevocationList: a provider version that
+// must never be installed, and why.
+type Revocation struct {
+	Provider addrs.Provider
+	Version  Version
+	Reason   string
+}
+
+// RevocationList is a set of provider versions a mirror has flagged as
+// unsafe to install, such as a release later found to contain a credential
+// leak or a build supply-chain compromise.
+//
+// Mirrors publish this as a revocations.json document alongside their
+// usual index, so that installers consult it even for a provider version
+// that's already pinned in a lock file, rather than only considering
+// revocations during initial resolution.
+type RevocationList struct {
+	revoked map[addrs.Provider]map[string]string // provider -> version string -> reason
+}
+
+// revocationListJSON is the on-disk representation of RevocationList.
+type revocationListJSON struct {
+	Revocations []struct {
+		Provider string `json:"provider"`
+		Version  string `json:"version"`
+		Reason   string `json:"reason"`
+	} `json:"revocations"`
+}
+
+// ParseRevocationList reads a revocations.json document as published by a
+// mirror.
+func ParseRevocationList(r io.Reader) (*RevocationList, error) {
+	var raw revocationListJSON
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid revocation list: %s", err)
+	}
+
+	list := &RevocationList{
+		revoked: make(map[addrs.Provider]map[string]string),
+	}
+	for _, item := range raw.Revocations {
+		provider, diags := addrs.ParseProviderSourceString(item.Provider)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("invalid provider address %q in revocation list: %s", item.Provider, diags.Err())
+		}
+		version, err := ParseVersion(item.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q for %s in revocation list: %s", item.Version, item.Provider, err)
+		}
+
+		versions, ok := list.revoked[provider]
+		if !ok {
+			versions = make(map[string]string)
+			list.revoked[provider] = versions
+		}
+		versions[version.String()] = item.Reason
+	}
+	return list, nil
+}
+
+// IsRevoked returns the recorded reason and true if provider at version
+// appears in the revocation list.
+func (l *RevocationList) IsRevoked(provider addrs.Provider, version Version) (reason string, revoked bool) {
+	if l == nil {
+		return "", false
+	}
+	reason, revoked = l.revoked[provider][version.String()]
+	return reason, revoked
+}
+
+// CheckRevocation returns a non-nil error describing why provider at
+// version must not be installed if it appears in list, unless allowOverride
+// is set, in which case the revocation is instead reported as a non-empty
+// warning and installation may proceed.
+//
+// allowOverride exists for emergencies where a revoked version must be
+// reinstalled anyway -- to roll back a bad mitigation, for example -- and
+// callers should require an explicit opt-in such as a command line flag
+// before passing true, rather than silently ignoring revocations.
+func CheckRevocation(list *RevocationList, provider addrs.Provider, version Version, allowOverride bool) (warning string, err error) {
+	reason, revoked := list.IsRevoked(provider, version)
+	if !revoked {
+		return "", nil
+	}
+	if allowOverride {
+		return fmt.Sprintf("provider %s %s is revoked (%s); installing anyway because of an override", provider, version, reason), nil
+	}
+	return "", fmt.Errorf("provider %s %s is revoked and must not be installed: %s", provider, version, reason)
+}
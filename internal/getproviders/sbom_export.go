@@ -0,0 +1,112 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SBOMComponent describes one installed provider package as a single
+// component of a software bill of materials, independent of which SBOM
+// format it ends up rendered into.
+type SBOMComponent struct {
+	// Name is the provider's fully-qualified address, such as
+	// "registry.terraform.io/hashicorp/aws".
+	Name string
+
+	Version string
+
+	// PackageURL is a "purl" (https://github.com/package-url/purl-spec)
+	// identifying this exact provider package, for cross-referencing
+	// against vulnerability databases that understand the terraform purl
+	// type.
+	PackageURL string
+
+	// SHA256Hash is the HashSchemeDir content hash of the package's
+	// unpacked directory, hex-encoded without the "h1:" scheme prefix.
+	SHA256Hash string
+
+	// PackageDir is the local filesystem location the package was
+	// installed from, recorded as this component's download location
+	// since installed packages have no further upstream URL available.
+	PackageDir string
+}
+
+// BuildSBOMComponents converts the result of SearchInstalledProviders into
+// SBOMComponents, computing a content hash for each package directory
+// along the way.
+func BuildSBOMComponents(installed []Installed) ([]SBOMComponent, error) {
+	components := make([]SBOMComponent, 0, len(installed))
+	for _, inst := range installed {
+		hash, err := PackageDirHash(inst.PackageDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %s", inst.PackageDir, err)
+		}
+
+		components = append(components, SBOMComponent{
+			Name:    inst.Provider.String(),
+			Version: inst.Version.String(),
+			PackageURL: fmt.Sprintf(
+				"pkg:terraform/%s/%s@%s?hostname=%s",
+				inst.Provider.Namespace, inst.Provider.Type, inst.Version, inst.Provider.Hostname,
+			),
+			SHA256Hash: strings.TrimPrefix(hash, string(HashSchemeDir)),
+			PackageDir: inst.PackageDir,
+		})
+	}
+	return components, nil
+}
+
+// cycloneDXDocument is a minimal rendering of the CycloneDX 1.3 JSON BOM
+// schema, covering only the fields BuildCycloneDXSBOM populates.
+type cycloneDXDocument struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version"`
+	PackageURL string              `json:"purl,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// BuildCycloneDXSBOM renders components as a CycloneDX 1.3 JSON software
+// bill of materials document, suitable for feeding into supply-chain
+// inventory tooling that understands that format.
+func BuildCycloneDXSBOM(components []SBOMComponent) ([]byte, error) {
+	doc := cycloneDXDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.3",
+		Version:     1,
+	}
+	for _, c := range components {
+		doc.Components = append(doc.Components, cycloneDXComponent{
+			Type:       "library",
+			Name:       c.Name,
+			Version:    c.Version,
+			PackageURL: c.PackageURL,
+			Hashes: []cycloneDXHash{
+				{Algorithm: "SHA-256", Content: c.SHA256Hash},
+			},
+			Properties: []cycloneDXProperty{
+				{Name: "terraform:packageDir", Value: c.PackageDir},
+			},
+		})
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
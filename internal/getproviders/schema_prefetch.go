@@ -0,0 +1,76 @@
+package getproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// schemaCacheFilename is the name of the cached schema bundle file
+// PrefetchPackageSchema writes into an installed package's directory,
+// alongside its extracted provider executable.
+const schemaCacheFilename = ".terraform-schema.json"
+
+// PrefetchPackageSchema downloads the schema bundle meta advertises via
+// SchemaDocumentURL, if any, and caches it in installDir -- normally an
+// Installed value's PackageDir -- so that a tool like a language server can
+// read a provider's schema straight off disk without ever executing its
+// plugin binary.
+//
+// It returns false without error, doing nothing else, when meta has no
+// SchemaDocumentURL: not every registry publishes schema bundles, and a
+// caller installing a batch of providers shouldn't need to special-case
+// that absence itself. A nil client uses http.DefaultClient.
+func PrefetchPackageSchema(client *http.Client, meta PackageMeta, installDir string) (bool, error) {
+	if meta.SchemaDocumentURL == "" {
+		return false, nil
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(meta.SchemaDocumentURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch provider schema bundle: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to fetch provider schema bundle: unexpected status %s", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to read provider schema bundle: %s", err)
+	}
+	// The schema bundle format itself is out of scope here, but confirming
+	// it's at least well-formed JSON catches a truncated or corrupted
+	// download before it's cached for some later, possibly much longer-
+	// lived, reader to trip over.
+	if !json.Valid(data) {
+		return false, fmt.Errorf("provider schema bundle is not valid JSON")
+	}
+
+	path := filepath.Join(installDir, schemaCacheFilename)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return false, fmt.Errorf("failed to write cached provider schema bundle: %s", err)
+	}
+	return true, nil
+}
+
+// CachedPackageSchema returns the schema bundle PrefetchPackageSchema
+// previously cached in installDir, if any, or (nil, false, nil) if no
+// schema bundle has been cached there.
+func CachedPackageSchema(installDir string) ([]byte, bool, error) {
+	path := filepath.Join(installDir, schemaCacheFilename)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read cached provider schema bundle: %s", err)
+	}
+	return data, true, nil
+}
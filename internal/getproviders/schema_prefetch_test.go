@@ -0,0 +1,74 @@
+package getproviders
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestPrefetchPackageSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"provider_schemas":{}}`))
+	}))
+	defer server.Close()
+
+	installDir, err := ioutil.TempDir("", "terraform-schema-prefetch")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(installDir)
+
+	meta := PackageMeta{SchemaDocumentURL: server.URL}
+	cached, err := PrefetchPackageSchema(nil, meta, installDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !cached {
+		t.Fatalf("expected schema to be cached")
+	}
+
+	data, ok, err := CachedPackageSchema(installDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected a cached schema bundle")
+	}
+	if string(data) != `{"provider_schemas":{}}` {
+		t.Errorf("wrong cached content: got %s", data)
+	}
+}
+
+func TestPrefetchPackageSchemaNoURL(t *testing.T) {
+	installDir, err := ioutil.TempDir("", "terraform-schema-prefetch")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(installDir)
+
+	cached, err := PrefetchPackageSchema(nil, PackageMeta{}, installDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cached {
+		t.Fatalf("expected no schema to be fetched without a SchemaDocumentURL")
+	}
+
+	if _, ok, err := CachedPackageSchema(installDir); err != nil || ok {
+		t.Fatalf("expected no cached schema, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestCachedPackageSchemaMissing(t *testing.T) {
+	installDir, err := ioutil.TempDir("", "terraform-schema-prefetch")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(installDir)
+
+	if _, ok, err := CachedPackageSchema(installDir); err != nil || ok {
+		t.Fatalf("expected no cached schema, got ok=%v err=%v", ok, err)
+	}
+}
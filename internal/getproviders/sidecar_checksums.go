@@ -0,0 +1,102 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sha256sumsFilename is the conventional name of a multi-package checksums
+// file, in the same "<hex digest>  <filename>" format sha256sum(1)
+// produces, that a mirror operator can drop directly into a provider's
+// type directory alongside its packed packages.
+const sha256sumsFilename = "SHA256SUMS"
+
+// sidecarSHA256 looks for a checksum for filename (a packed package's
+// filename, relative to typeDir) recorded by a mirror operator ahead of
+// time, either in a "<filename>.sha256" file of its own or as a line of a
+// shared SHA256SUMS file, so that PackageMeta can be populated without
+// reading and hashing the whole package -- often several hundred
+// megabytes -- on every single call.
+//
+// It returns ok false, with no error, if neither form of sidecar is
+// present or names this filename; that's the expected case for a mirror
+// populated by simply copying in package files, and callers should fall
+// back to hashing the package directly.
+func sidecarSHA256(typeDir, filename string) (sum [sha256.Size]byte, ok bool, err error) {
+	if sum, ok, err := readSingleSHA256SidecarFile(filepath.Join(typeDir, filename+".sha256")); ok || err != nil {
+		return sum, ok, err
+	}
+	return readSHA256SumsFile(filepath.Join(typeDir, sha256sumsFilename), filename)
+}
+
+// readSingleSHA256SidecarFile reads a "<filename>.sha256" sidecar, whose
+// conventional content is a single hex digest, optionally followed by
+// whitespace and the filename it covers in the same format sha256sum(1)
+// produces for a single file.
+func readSingleSHA256SidecarFile(path string) (sum [sha256.Size]byte, ok bool, err error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return sum, false, nil
+	}
+	if err != nil {
+		return sum, false, fmt.Errorf("failed to read %s: %s", path, err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return sum, false, fmt.Errorf("%s is empty", path)
+	}
+
+	sum, err = parseSHA256Hex(fields[0])
+	if err != nil {
+		return sum, false, fmt.Errorf("invalid checksum in %s: %s", path, err)
+	}
+	return sum, true, nil
+}
+
+// readSHA256SumsFile reads a shared SHA256SUMS-style file and returns the
+// digest recorded for filename, if any of its lines name it.
+func readSHA256SumsFile(path, filename string) (sum [sha256.Size]byte, ok bool, err error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return sum, false, nil
+	}
+	if err != nil {
+		return sum, false, fmt.Errorf("failed to read %s: %s", path, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum(1) prefixes the filename with "*" for binary mode.
+		name := strings.TrimPrefix(fields[1], "*")
+		if name != filename {
+			continue
+		}
+		sum, err = parseSHA256Hex(fields[0])
+		if err != nil {
+			return sum, false, fmt.Errorf("invalid checksum for %s in %s: %s", filename, path, err)
+		}
+		return sum, true, nil
+	}
+	return sum, false, nil
+}
+
+func parseSHA256Hex(s string) (sum [sha256.Size]byte, err error) {
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return sum, err
+	}
+	if len(decoded) != sha256.Size {
+		return sum, fmt.Errorf("wrong length: got %d bytes, want %d", len(decoded), sha256.Size)
+	}
+	copy(sum[:], decoded)
+	return sum, nil
+}
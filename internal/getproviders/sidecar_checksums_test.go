@@ -0,0 +1,78 @@
+package getproviders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSidecarSHA256SingleFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-sidecar-checksums")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	want := sha256.Sum256([]byte("fake package contents"))
+	sidecar := filepath.Join(dir, "package.zip.sha256")
+	if err := ioutil.WriteFile(sidecar, []byte(hex.EncodeToString(want[:])+"  package.zip\n"), 0644); err != nil {
+		t.Fatalf("failed to write sidecar: %s", err)
+	}
+
+	got, ok, err := sidecarSHA256(dir, "package.zip")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected sidecar to be found")
+	}
+	if got != want {
+		t.Errorf("wrong checksum: got %x, want %x", got, want)
+	}
+}
+
+func TestSidecarSHA256SumsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-sidecar-checksums")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	want := sha256.Sum256([]byte("fake package contents"))
+	content := hex.EncodeToString(want[:]) + "  terraform-provider-test_1.0.0_linux_amd64.zip\n" +
+		"00000000" + "00000000" + "00000000" + "00000000" + "00000000" + "00000000" + "00000000" + "00000000" +
+		"  terraform-provider-other_1.0.0_linux_amd64.zip\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, sha256sumsFilename), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write SHA256SUMS: %s", err)
+	}
+
+	got, ok, err := sidecarSHA256(dir, "terraform-provider-test_1.0.0_linux_amd64.zip")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected SHA256SUMS entry to be found")
+	}
+	if got != want {
+		t.Errorf("wrong checksum: got %x, want %x", got, want)
+	}
+}
+
+func TestSidecarSHA256Missing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraform-sidecar-checksums")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, ok, err := sidecarSHA256(dir, "package.zip")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ok {
+		t.Fatalf("expected no sidecar to be found")
+	}
+}
@@ -0,0 +1,104 @@
+package getproviders
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// SingleFlightSource is a Source that wraps another Source and coalesces
+// concurrent duplicate requests into a single call to the underlying
+// source, so that (for example) two modules requiring the same provider do
+// not each cause their own outgoing request to a MultiSource backend.
+//
+// Unlike MemoizeSource, SingleFlightSource does not retain results once all
+// of the callers waiting on them have been satisfied; it only deduplicates
+// requests that are in flight at the same time. Wrap a SingleFlightSource
+// around the result of NewMemoizeSource, or vice versa, to get both
+// behaviors.
+type SingleFlightSource struct {
+	underlying Source
+
+	mu                sync.Mutex
+	availableVersions map[addrs.Provider]*availableVersionsCall
+	packageMetas      map[memoizePackageMetaCall]*packageMetaCall
+}
+
+var _ Source = (*SingleFlightSource)(nil)
+
+// NewSingleFlightSource constructs and returns a new SingleFlightSource
+// that wraps the given underlying source.
+func NewSingleFlightSource(underlying Source) *SingleFlightSource {
+	return &SingleFlightSource{
+		underlying:        underlying,
+		availableVersions: make(map[addrs.Provider]*availableVersionsCall),
+		packageMetas:      make(map[memoizePackageMetaCall]*packageMetaCall),
+	}
+}
+
+type availableVersionsCall struct {
+	done   chan struct{}
+	result VersionList
+	err    error
+}
+
+type packageMetaCall struct {
+	done   chan struct{}
+	result PackageMeta
+	err    error
+}
+
+// AvailableVersions requests the available versions from the underlying
+// source, coalescing with any other concurrent call for the same provider.
+func (s *SingleFlightSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	s.mu.Lock()
+	if call, inFlight := s.availableVersions[provider]; inFlight {
+		s.mu.Unlock()
+		<-call.done
+		return call.result, call.err
+	}
+
+	call := &availableVersionsCall{done: make(chan struct{})}
+	s.availableVersions[provider] = call
+	s.mu.Unlock()
+
+	call.result, call.err = s.underlying.AvailableVersions(provider)
+
+	s.mu.Lock()
+	delete(s.availableVersions, provider)
+	s.mu.Unlock()
+	close(call.done)
+
+	return call.result, call.err
+}
+
+// PackageMeta requests package metadata from the underlying source,
+// coalescing with any other concurrent call for the same provider, version,
+// and target platform.
+func (s *SingleFlightSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	key := memoizePackageMetaCall{
+		Provider: provider,
+		Version:  version,
+		Target:   target,
+	}
+
+	s.mu.Lock()
+	if call, inFlight := s.packageMetas[key]; inFlight {
+		s.mu.Unlock()
+		<-call.done
+		return call.result, call.err
+	}
+
+	call := &packageMetaCall{done: make(chan struct{})}
+	s.packageMetas[key] = call
+	s.mu.Unlock()
+
+	call.result, call.err = s.underlying.PackageMeta(provider, version, target)
+
+	s.mu.Lock()
+	delete(s.packageMetas, key)
+	s.mu.Unlock()
+	close(call.done)
+
+	return call.result, call.err
+}
@@ -0,0 +1,89 @@
+package getproviders
+
+import (
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// SourceHooks lets an embedder observe and, in limited ways, influence the
+// result of each query a HookedSource makes against its underlying Source.
+//
+// Every field is optional; a nil field means that phase has no hook to
+// run. This is the extension point for company-specific integrations --
+// ticket checks, inventory registration, policy vetoes -- that need to see
+// or alter a Source's results without implementing Source themselves or
+// forking the installer.
+type SourceHooks struct {
+	// BeforeAvailableVersions is called before querying the underlying
+	// source for provider's available versions. Returning a non-nil error
+	// aborts the query, and that error is returned to the original caller
+	// in place of whatever the underlying source would have produced.
+	BeforeAvailableVersions func(provider addrs.Provider) error
+
+	// AfterAvailableVersions is called with the underlying source's
+	// result, and returns the VersionList and error HookedSource should
+	// actually return, allowing either to be annotated or replaced
+	// outright. A hook that has nothing to add should return the values it
+	// was given unmodified.
+	AfterAvailableVersions func(provider addrs.Provider, versions VersionList, err error) (VersionList, error)
+
+	// BeforePackageMeta is called before querying the underlying source
+	// for a specific package's metadata. Returning a non-nil error aborts
+	// the query, and that error is returned to the original caller in
+	// place of whatever the underlying source would have produced.
+	BeforePackageMeta func(provider addrs.Provider, version Version, target Platform) error
+
+	// AfterPackageMeta is called with the underlying source's result, and
+	// returns the PackageMeta and error HookedSource should actually
+	// return, allowing either to be annotated or replaced outright.
+	AfterPackageMeta func(provider addrs.Provider, version Version, target Platform, meta PackageMeta, err error) (PackageMeta, error)
+}
+
+// HookedSource wraps another Source, running a SourceHooks's callbacks
+// before and after each query so that an embedder can observe, annotate,
+// or veto results without implementing Source itself.
+type HookedSource struct {
+	underlying Source
+	hooks      SourceHooks
+}
+
+var _ Source = (*HookedSource)(nil)
+
+// NewHookedSource constructs a HookedSource that wraps underlying, running
+// hooks's callbacks around each query made against it.
+func NewHookedSource(underlying Source, hooks SourceHooks) *HookedSource {
+	return &HookedSource{underlying: underlying, hooks: hooks}
+}
+
+// AvailableVersions implements Source, running BeforeAvailableVersions and
+// AfterAvailableVersions around the underlying source's own implementation.
+func (s *HookedSource) AvailableVersions(provider addrs.Provider) (VersionList, error) {
+	if s.hooks.BeforeAvailableVersions != nil {
+		if err := s.hooks.BeforeAvailableVersions(provider); err != nil {
+			return nil, err
+		}
+	}
+
+	versions, err := s.underlying.AvailableVersions(provider)
+
+	if s.hooks.AfterAvailableVersions != nil {
+		versions, err = s.hooks.AfterAvailableVersions(provider, versions, err)
+	}
+	return versions, err
+}
+
+// PackageMeta implements Source, running BeforePackageMeta and
+// AfterPackageMeta around the underlying source's own implementation.
+func (s *HookedSource) PackageMeta(provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	if s.hooks.BeforePackageMeta != nil {
+		if err := s.hooks.BeforePackageMeta(provider, version, target); err != nil {
+			return PackageMeta{}, err
+		}
+	}
+
+	meta, err := s.underlying.PackageMeta(provider, version, target)
+
+	if s.hooks.AfterPackageMeta != nil {
+		meta, err = s.hooks.AfterPackageMeta(provider, version, target, meta, err)
+	}
+	return meta, err
+}
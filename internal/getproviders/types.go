@@ -2,7 +2,11 @@ package getproviders
 
 import (
 	"crypto/sha256"
+	"fmt"
+	"io/fs"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/apparentlymart/go-versions/versions"
 )
@@ -30,6 +34,39 @@ func (p Platform) String() string {
 	return p.OS + "_" + p.Arch
 }
 
+// ParsePlatform parses a string of the form "os_arch", as produced by
+// Platform.String, into a Platform value.
+func ParsePlatform(str string) (Platform, error) {
+	parts := strings.SplitN(str, "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("must be two words separated by an underscore")
+	}
+	return Platform{
+		OS:   parts[0],
+		Arch: parts[1],
+	}, nil
+}
+
+// MarshalText implements encoding.TextMarshaler, so that a Platform
+// embedded in JSON or other text-based formats encodes as its canonical
+// "os_arch" string rather than as an object with OS and Arch fields,
+// matching the form external tools already see in filenames and directory
+// layouts.
+func (p Platform) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, the counterpart to
+// MarshalText, by delegating to ParsePlatform.
+func (p *Platform) UnmarshalText(text []byte) error {
+	parsed, err := ParsePlatform(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
 // CurrentPlatform is the platform where the current program is running.
 //
 // If attempting to install providers for use on the same system where the
@@ -55,12 +92,89 @@ type PackageMeta struct {
 	Location  PackageLocation
 	SHA256Sum [sha256.Size]byte
 
-	// TODO: Extra metadata for signature verification
+	// Hashes is zero or more additional hash strings, in the same format
+	// used by LockedProvider.Hashes, that a Source was able to compute or
+	// obtain for this package beyond SHA256Sum. In particular, a Source
+	// backed by an already-unpacked directory (such as
+	// FilesystemMirrorSource's unpacked layout) populates this with an
+	// HashSchemeDir ("h1:") hash instead of SHA256Sum, since the original
+	// zip bytes aren't available to hash that way.
+	Hashes []string
+
+	// SigningKeys is zero or more ASCII-armored OpenPGP public keys that
+	// the source trusts to sign this package's checksums, typically the
+	// provider publisher's own key as reported by the registry's key
+	// listing for this provider's namespace.
+	SigningKeys []string
+
+	// ChecksumsSHA256SumsURL and ChecksumsSignatureURL, when both
+	// non-empty, point to a SHA256SUMS-style document covering this
+	// package and a detached OpenPGP signature of that document,
+	// respectively. Together with SigningKeys these let the installer
+	// authenticate the package more strongly than by trusting SHA256Sum
+	// alone, which only reflects what the source's own (potentially
+	// unsigned) API response claimed.
+	ChecksumsSHA256SumsURL string
+	ChecksumsSignatureURL  string
+
+	// SchemaDocumentURL, when non-empty, points to a pre-built bundle of
+	// this package's provider schema that a Source obtained from the
+	// registry, separately from the package itself. A caller that only
+	// needs schema information -- a language server offering completion,
+	// in particular -- can fetch this instead of installing and executing
+	// the provider binary just to ask it for its own schema. Not every
+	// Source or registry publishes these, so this is commonly empty.
+	SchemaDocumentURL string
+
+	// SizeBytes is the on-disk size of the package, in bytes, if the
+	// Source was able to determine it cheaply -- typically by stat'ing a
+	// local file during scanning -- and zero otherwise. UI code can use
+	// this to show a download or install size; mirror-sync tools can use
+	// it to detect that a package has changed without re-hashing it.
+	SizeBytes int64
+
+	// ModTime is the package's last-modified time, if the Source was able
+	// to determine it cheaply during scanning, and the zero Time
+	// otherwise. As with SizeBytes, this exists mainly as a cheap
+	// change-detection signal for mirror-sync tools, not as an
+	// authoritative record of when a provider version was published.
+	ModTime time.Time
+}
+
+// Authentication returns the strongest PackageAuthentication this package's
+// metadata supports.
+//
+// If the source supplied a checksums document and a detached signature of
+// it (the bytes downloaded from ChecksumsSHA256SumsURL and
+// ChecksumsSignatureURL) along with at least one trusted key in
+// SigningKeys, the result verifies both that the signature was made by one
+// of those keys and that this package's own checksum matches the entry for
+// Filename in that document. Otherwise it falls back to comparing the
+// package's checksum directly against SHA256Sum, which every Source can
+// provide regardless of what other trust metadata it has available.
+//
+// Pass nil for checksumsDocument and checksumsSignature if the caller
+// hasn't downloaded them, or PackageMeta didn't advertise them in the
+// first place; Authentication falls back to the plain checksum check in
+// either case.
+//
+// keyExpiryPolicy controls how strictly a signature made by a since-expired
+// signing key is treated; pass the zero value to reject it outright.
+func (m PackageMeta) Authentication(checksumsDocument, checksumsSignature []byte, keyExpiryPolicy KeyExpiryPolicy) PackageAuthentication {
+	if len(checksumsDocument) == 0 || len(checksumsSignature) == 0 || len(m.SigningKeys) == 0 {
+		return NewChecksumAuthentication(m.SHA256Sum)
+	}
+
+	return PackageAuthenticationAll(
+		NewArchiveChecksumMatchAuthentication(checksumsDocument, m.Filename),
+		newAnyKeySignatureAuthentication(checksumsDocument, checksumsSignature, m.SigningKeys, keyExpiryPolicy),
+	)
 }
 
 // PackageLocation represents a location where a provider distribution package
 // can be obtained. A value of this type contains one of the following
-// concrete types: PackageLocalArchive, PackageLocalDir, or PackageHTTPURL.
+// concrete types: PackageLocalArchive, PackageLocalDir, PackageHTTPURL, or
+// PackageFSArchive.
 type PackageLocation interface {
 	packageLocation()
 }
@@ -86,3 +200,15 @@ func (p PackageLocalDir) packageLocation() {}
 type PackageHTTPURL string
 
 func (p PackageHTTPURL) packageLocation() {}
+
+// PackageFSArchive is the location of a provider distribution archive file
+// within an fs.FS, for a package returned by FSMirrorSource. Unlike
+// PackageLocalArchive, Path isn't necessarily a real filesystem path that
+// other packages' path/filepath or os calls can use directly; it must be
+// read through FS using the io/fs APIs.
+type PackageFSArchive struct {
+	FS   fs.FS
+	Path string
+}
+
+func (p PackageFSArchive) packageLocation() {}
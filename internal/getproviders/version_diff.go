@@ -0,0 +1,75 @@
+package getproviders
+
+import (
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// VersionListDiff describes the versions added and removed between two
+// VersionList snapshots of the same provider, as produced by
+// DiffVersionLists.
+type VersionListDiff struct {
+	Added   VersionList
+	Removed VersionList
+}
+
+// DiffVersionLists compares an older and a newer VersionList for the same
+// provider and reports which versions were added and which were removed.
+// Versions present in both are unchanged and appear in neither list.
+func DiffVersionLists(older, newer VersionList) VersionListDiff {
+	olderSet := make(map[string]bool, len(older))
+	for _, v := range older {
+		olderSet[v.String()] = true
+	}
+	newerSet := make(map[string]bool, len(newer))
+	for _, v := range newer {
+		newerSet[v.String()] = true
+	}
+
+	var diff VersionListDiff
+	for _, v := range newer {
+		if !olderSet[v.String()] {
+			diff.Added = append(diff.Added, v)
+		}
+	}
+	for _, v := range older {
+		if !newerSet[v.String()] {
+			diff.Removed = append(diff.Removed, v)
+		}
+	}
+	diff.Added.Sort()
+	diff.Removed.Sort()
+	return diff
+}
+
+// MirrorDiff maps each provider whose available versions changed between
+// two scans of a mirror to the VersionListDiff describing what changed.
+type MirrorDiff map[addrs.Provider]VersionListDiff
+
+// DiffMirrorScans compares two scans of a mirror, as returned by
+// AllAvailablePackages, and reports the VersionListDiff for each provider
+// whose available versions changed between them, powering "what changed in
+// the mirror since yesterday" reports for mirror operators. Providers with
+// no change are omitted from the result. A provider present in only one of
+// the two scans is treated as if the other scan had reported it with no
+// versions available at all.
+func DiffMirrorScans(older, newer map[addrs.Provider]VersionList) MirrorDiff {
+	seen := make(map[addrs.Provider]bool, len(older))
+	diff := make(MirrorDiff)
+
+	for provider, olderVersions := range older {
+		seen[provider] = true
+		if d := DiffVersionLists(olderVersions, newer[provider]); len(d.Added) > 0 || len(d.Removed) > 0 {
+			diff[provider] = d
+		}
+	}
+	for provider, newerVersions := range newer {
+		if seen[provider] {
+			continue
+		}
+		if d := DiffVersionLists(nil, newerVersions); len(d.Added) > 0 || len(d.Removed) > 0 {
+			diff[provider] = d
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,30 @@
+package getproviders
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apparentlymart/go-versions/versions"
+)
+
+func TestDiffVersionLists(t *testing.T) {
+	older := VersionList{
+		versions.MustParseVersion("1.0.0"),
+		versions.MustParseVersion("1.1.0"),
+	}
+	newer := VersionList{
+		versions.MustParseVersion("1.1.0"),
+		versions.MustParseVersion("1.2.0"),
+	}
+
+	got := DiffVersionLists(older, newer)
+
+	wantAdded := VersionList{versions.MustParseVersion("1.2.0")}
+	wantRemoved := VersionList{versions.MustParseVersion("1.0.0")}
+	if !reflect.DeepEqual(got.Added, wantAdded) {
+		t.Errorf("wrong added versions\ngot:  %#v\nwant: %#v", got.Added, wantAdded)
+	}
+	if !reflect.DeepEqual(got.Removed, wantRemoved) {
+		t.Errorf("wrong removed versions\ngot:  %#v\nwant: %#v", got.Removed, wantRemoved)
+	}
+}
@@ -0,0 +1,49 @@
+package getproviders
+
+import (
+	"github.com/hashicorp/terraform/addrs"
+)
+
+// VersionStreamSource is an optional extension to Source for sources that
+// are able to produce their available versions incrementally, so that a
+// caller doing constraint-based selection over a registry with a very
+// large number of published versions can stop consuming early once it has
+// seen enough candidates, rather than waiting for the whole list.
+type VersionStreamSource interface {
+	Source
+
+	// AvailableVersionsStream behaves like AvailableVersions except that
+	// versions are delivered one at a time to the given callback, in the
+	// same order AvailableVersions would return them, rather than all at
+	// once. The callback returns false to request that the stream stop
+	// early, in which case AvailableVersionsStream returns nil without
+	// necessarily having consumed the whole underlying result.
+	AvailableVersionsStream(provider addrs.Provider, f func(Version) (keepGoing bool)) error
+}
+
+// StreamAvailableVersions calls f once for each version available for the
+// given provider from source, in ascending precedence order, stopping
+// early if f returns false.
+//
+// If source implements VersionStreamSource then its streaming
+// implementation is used directly so that it can short-circuit its own
+// underlying query. Otherwise this function falls back to calling
+// AvailableVersions and iterating over the result locally, so callers can
+// always use StreamAvailableVersions regardless of what kind of Source
+// they have.
+func StreamAvailableVersions(source Source, provider addrs.Provider, f func(Version) (keepGoing bool)) error {
+	if streamer, ok := source.(VersionStreamSource); ok {
+		return streamer.AvailableVersionsStream(provider, f)
+	}
+
+	versions, err := source.AvailableVersions(provider)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if !f(v) {
+			break
+		}
+	}
+	return nil
+}
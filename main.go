@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	"github.com/hashicorp/go-plugin"
+	svchost "github.com/hashicorp/terraform-svchost"
 	"github.com/hashicorp/terraform-svchost/disco"
 	"github.com/hashicorp/terraform/command/cliconfig"
 	"github.com/hashicorp/terraform/command/format"
@@ -165,11 +166,22 @@ func wrappedMain() int {
 	services := disco.NewWithCredentialsSource(credsSrc)
 	services.SetUserAgent(httpclient.TerraformUserAgent(version.String()))
 
+	if config.DefaultProviderRegistryHost != "" {
+		host, err := svchost.ForComparison(config.DefaultProviderRegistryHost)
+		if err != nil {
+			Ui.Error(fmt.Sprintf("Invalid default_provider_registry_host %q in CLI configuration: %s\n\n", config.DefaultProviderRegistryHost, err))
+		} else {
+			getproviders.SetDefaultRegistryHost(host)
+		}
+	}
+
 	// For the moment, we just always use the registry source to install
 	// direct from a registry. In future there should be a mechanism to
 	// configure providers sources from the CLI config, which will then
 	// change how we construct this object.
 	providerSrc := getproviders.NewRegistrySource(services)
+	providerSrc.TrustedProxyCACertPEM = config.ProviderTrustedProxyCACertPEM
+	providerSrc.ProxyURL = config.ProviderProxyURL
 
 	// Initialize the backends.
 	backendInit.Init(services)